@@ -0,0 +1,470 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync"
+	"time"
+
+	"thingspanel-TDengine/logger"
+
+	"gitee.com/chunanyong/zorm"
+	"github.com/spf13/viper"
+)
+
+// key目录表
+const SuperTableTvCatalog = "ts_kv_catalog"
+
+// 设备上报过的key，Count为近似数据点数(未持久化的计数在服务重启时丢失)
+type CatalogEntry struct {
+	DeviceId  string
+	Key       string
+	ValueType string // bool、number、string
+	FirstTs   time.Time
+	LastTs    time.Time
+	Count     int64
+	TenantId  string
+}
+
+// ts_kv_catalog的一行，每次持久化只写入本周期的增量，读取时按device_id、k汇总
+type catalogRow struct {
+	zorm.EntityStruct
+	Ts         time.Time `column:"ts"`
+	DeviceId   string    `column:"device_id"`
+	K          string    `column:"k"`
+	ValueType  string    `column:"value_type"`
+	FirstTs    time.Time `column:"first_ts"`
+	LastTs     time.Time `column:"last_ts"`
+	PointCount int64     `column:"point_count"`
+	TenantId   string    `column:"tenant_id"`
+	TableName  string
+}
+
+func (entity *catalogRow) GetTableName() string {
+	return entity.TableName
+}
+
+func (entity *catalogRow) GetPKColumnName() string {
+	return ""
+}
+
+// 写入后尚未持久化的增量，device_id -> key -> 增量
+var catalogPending = struct {
+	mu     sync.Mutex
+	deltas map[string]map[string]*CatalogEntry
+}{deltas: make(map[string]map[string]*CatalogEntry)}
+
+// 每个进程只写自己的子表，各副本的增量互不覆盖，压缩时也只处理自己的子表
+// next为下一行可用的时间戳，保证同一子表内的行时间戳不重复
+var catalogWriter struct {
+	mu    sync.Mutex
+	table string
+	next  time.Time
+}
+
+// 每次回查已持久化目录的最多设备数
+const catalogQueryBatch = 500
+
+// 生成目录的标记子表，每个尝试生成的副本写入一行(device_id为空，k为副本标识)，最早写入的副本负责生成
+const catalogSeedTable = SuperTableTvCatalog + "_seed"
+
+// 写入标记后等待其他副本的标记写入的时长
+var catalogSeedSettle = 5 * time.Second
+
+// 初始化key目录：建表，目录为空(首次部署)时从ts_kv全量生成一次，启动定时持久化和压缩
+func initCatalog() error {
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("CREATE STABLE if not exists %s.%s (ts TIMESTAMP, device_id NCHAR(64), k NCHAR(64), value_type NCHAR(8), first_ts TIMESTAMP, last_ts TIMESTAMP, point_count BIGINT, tenant_id NCHAR(64)) TAGS (model_id BINARY(64), model_name BINARY(64))",
+		DBName, SuperTableTvCatalog))
+	_, err := zorm.UpdateFinder(ctx, finder)
+	if err != nil {
+		logger.Db.Error("failed to create stable", "stable", SuperTableTvCatalog, "error", err)
+		return err
+	}
+
+	instance := catalogInstance()
+	catalogWriter.table = SuperTableTvCatalog + "_" + instance
+	if err := createSubTablesByName(SuperTableTvCatalog, catalogWriter.table, instance); err != nil {
+		return err
+	}
+	if err := createSubTablesByName(SuperTableTvCatalog, catalogSeedTable, "seed"); err != nil {
+		return err
+	}
+
+	if err := seedCatalog(instance); err != nil {
+		// 生成失败不影响服务，之后写入的key仍会进入目录
+		logger.Db.Warn("failed to seed key catalog", "error", err)
+	}
+
+	go flushCatalogLoop()
+	return nil
+}
+
+// 本进程的目录子表名后缀，由主机名和grpc端口生成，重启后沿用同一子表
+func catalogInstance() string {
+	host, _ := os.Hostname()
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%s:%d", host, viper.GetInt("grpc.port"))))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// 为n行分配连续且递增的时间戳，返回第一行的时间戳
+func nextCatalogTs(n int) time.Time {
+	catalogWriter.mu.Lock()
+	defer catalogWriter.mu.Unlock()
+	ts := time.Now().Truncate(time.Microsecond)
+	if ts.Before(catalogWriter.next) {
+		ts = catalogWriter.next
+	}
+	catalogWriter.next = ts.Add(time.Duration(n) * time.Microsecond)
+	return ts
+}
+
+// 目录为空时按设备和key统计ts_kv生成目录，多个副本同时启动时只由最早写入标记的副本生成
+func seedCatalog(instance string) error {
+	// 需要扫描全部数据，不使用默认查询超时
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT COUNT(*) AS cnt FROM %s.%s WHERE device_id <> ''", DBName, SuperTableTvCatalog))
+	dataMap, err := QueryMap(ctx, finder, nil)
+	if err != nil {
+		return err
+	}
+	if len(dataMap) > 0 && toInt(dataMap[0]["cnt"], 0) > 0 {
+		return nil
+	}
+
+	owner, err := catalogSeedOwner(ctx)
+	if err != nil {
+		return err
+	}
+	if owner == "" {
+		// 没有副本生成过，写入本副本的标记，等待后以最早的标记为准
+		now := time.Now()
+		marker := &catalogRow{Ts: now, K: instance, FirstTs: now, LastTs: now, TableName: DBName + "." + catalogSeedTable}
+		if _, err := zorm.InsertSlice(ctx, []zorm.IEntityStruct{marker}); err != nil {
+			return err
+		}
+		time.Sleep(catalogSeedSettle)
+		if owner, err = catalogSeedOwner(ctx); err != nil {
+			return err
+		}
+	}
+	// 生成失败的副本重启后沿用同一标识，会重新生成
+	if owner != instance {
+		logger.Db.Info("key catalog is seeded by another replica", "owner", owner)
+		return nil
+	}
+
+	finder = zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT device_id,k,FIRST(ts) AS first_ts,LAST(ts) AS last_ts,COUNT(*) AS cnt,LAST_ROW(bool_v) AS bool_v,LAST_ROW(number_v) AS number_v,LAST_ROW(string_v) AS string_v,LAST_ROW(tenant_id) AS tenant_id FROM %s.%s PARTITION BY device_id,k",
+		DBName, SuperTableTv))
+	dataMap, err = QueryMap(ctx, finder, nil)
+	if err != nil {
+		return err
+	}
+
+	var entries []*CatalogEntry
+	for _, mp := range dataMap {
+		firstTs, ok1 := mp["first_ts"].(time.Time)
+		lastTs, ok2 := mp["last_ts"].(time.Time)
+		if !ok1 || !ok2 {
+			continue
+		}
+		entries = append(entries, &CatalogEntry{
+			DeviceId:  fmt.Sprintf("%v", mp["device_id"]),
+			Key:       fmt.Sprintf("%v", mp["k"]),
+			ValueType: valueType(toInt(mp["bool_v"], BoolDefault), toFloat(mp["number_v"], NumberDefault), toString(mp["string_v"], StringDefault)),
+			FirstTs:   firstTs,
+			LastTs:    lastTs,
+			Count:     int64(toInt(mp["cnt"], 0)),
+			TenantId:  toString(mp["tenant_id"], ""),
+		})
+	}
+	if err := insertCatalog(entries, nextCatalogTs(len(entries))); err != nil {
+		return err
+	}
+	logger.Db.Info("key catalog seeded", "keys", len(entries))
+	return nil
+}
+
+// 最早写入生成标记的副本标识，没有标记时返回空
+func catalogSeedOwner(ctx context.Context) (string, error) {
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT k FROM %s.%s ORDER BY ts ASC LIMIT 1", DBName, catalogSeedTable))
+	dataMap, err := QueryMap(ctx, finder, nil)
+	if err != nil || len(dataMap) == 0 {
+		return "", err
+	}
+	return toString(dataMap[0]["k"], ""), nil
+}
+
+// 按非占位值的列判断值类型
+func valueType(boolV int, numberV float64, stringV string) string {
+	switch {
+	case boolV != BoolDefault:
+		return "bool"
+	case stringV != StringDefault:
+		return "string"
+	case numberV != NumberDefault:
+		return "number"
+	}
+	return ""
+}
+
+// 写入成功后累计目录增量
+func updateCatalog(demos []zorm.IEntityStruct) {
+	catalogPending.mu.Lock()
+	defer catalogPending.mu.Unlock()
+	for _, e := range demos {
+		demo, ok := e.(*Demo)
+		if !ok {
+			continue
+		}
+		keys, ok := catalogPending.deltas[demo.DeviceId]
+		if !ok {
+			keys = make(map[string]*CatalogEntry)
+			catalogPending.deltas[demo.DeviceId] = keys
+		}
+		mergeCatalog(keys, &CatalogEntry{
+			DeviceId:  demo.DeviceId,
+			Key:       demo.K,
+			ValueType: valueType(demo.BoolV, demo.NumberV, demo.StringV),
+			FirstTs:   demo.Ts,
+			LastTs:    demo.Ts,
+			Count:     1,
+			TenantId:  demo.TenantId,
+		})
+	}
+}
+
+// 把增量d合并到keys中，值类型和租户取较新的
+func mergeCatalog(keys map[string]*CatalogEntry, d *CatalogEntry) {
+	cur, ok := keys[d.Key]
+	if !ok {
+		c := *d
+		keys[d.Key] = &c
+		return
+	}
+	cur.Count += d.Count
+	if d.FirstTs.Before(cur.FirstTs) {
+		cur.FirstTs = d.FirstTs
+	}
+	if !d.LastTs.Before(cur.LastTs) {
+		cur.LastTs = d.LastTs
+		if d.ValueType != "" {
+			cur.ValueType = d.ValueType
+		}
+		if d.TenantId != "" {
+			cur.TenantId = d.TenantId
+		}
+	}
+}
+
+func catalogFlushInterval() time.Duration {
+	interval := viper.GetDuration("db.catalog_flush_interval") * time.Second
+	if interval <= 0 {
+		interval = 60 * time.Second
+	}
+	return interval
+}
+
+// 早于该时长的增量行在压缩时合并为每个device_id、k一行
+func catalogRetention() time.Duration {
+	retention := viper.GetDuration("db.catalog_retention") * 24 * time.Hour
+	if retention <= 0 {
+		retention = 7 * 24 * time.Hour
+	}
+	return retention
+}
+
+func flushCatalogLoop() {
+	tc := time.NewTicker(catalogFlushInterval())
+	defer tc.Stop()
+	compact := time.NewTicker(time.Hour)
+	defer compact.Stop()
+	for {
+		select {
+		case <-tc.C:
+			flushCatalog()
+		case <-compact.C:
+			if err := compactCatalog(); err != nil {
+				logger.Db.Error("failed to compact key catalog", "table", catalogWriter.table, "error", err)
+			}
+		}
+	}
+}
+
+// 将增量写入本进程的子表，失败时增量放回下次重试
+func flushCatalog() {
+	catalogPending.mu.Lock()
+	pending := catalogPending.deltas
+	catalogPending.deltas = make(map[string]map[string]*CatalogEntry)
+	catalogPending.mu.Unlock()
+
+	var entries []*CatalogEntry
+	for _, keys := range pending {
+		for _, d := range keys {
+			entries = append(entries, d)
+		}
+	}
+	if len(entries) == 0 {
+		return
+	}
+
+	if err := insertCatalog(entries, nextCatalogTs(len(entries))); err != nil {
+		logger.Db.Error("failed to flush key catalog", "rows", len(entries), "error", err)
+		catalogPending.mu.Lock()
+		defer catalogPending.mu.Unlock()
+		for deviceId, keys := range pending {
+			cur, ok := catalogPending.deltas[deviceId]
+			if !ok {
+				catalogPending.deltas[deviceId] = keys
+				continue
+			}
+			for _, d := range keys {
+				mergeCatalog(cur, d)
+			}
+		}
+	}
+}
+
+// 写入本进程的子表，第i行的时间戳为ts+i微秒
+func insertCatalog(entries []*CatalogEntry, ts time.Time) error {
+	rows := make([]zorm.IEntityStruct, 0, len(entries))
+	for i, e := range entries {
+		rows = append(rows, &catalogRow{
+			Ts:         ts.Add(time.Duration(i) * time.Microsecond),
+			DeviceId:   e.DeviceId,
+			K:          e.Key,
+			ValueType:  e.ValueType,
+			FirstTs:    e.FirstTs,
+			LastTs:     e.LastTs,
+			PointCount: e.Count,
+			TenantId:   e.TenantId,
+			TableName:  DBName + "." + catalogWriter.table,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	_, err := zorm.InsertSlice(ctx, rows)
+	return err
+}
+
+// 压缩本进程子表中早于保留时长的增量：每个device_id、k汇总为一行，
+// 汇总行写在最早一行之前的空闲时间戳上，写入成功后再删除被汇总的行，
+// 中途失败最多重复计数，不会丢失first_ts
+func compactCatalog() error {
+	table := DBName + "." + catalogWriter.table
+	horizon := time.Now().Add(-catalogRetention()).Truncate(time.Hour)
+
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT FIRST(ts) AS ts,COUNT(*) AS cnt FROM %s WHERE ts < ?", table), horizon)
+	dataMap, err := QueryMap(ctx, finder, nil)
+	if err != nil {
+		return err
+	}
+	if len(dataMap) == 0 {
+		return nil
+	}
+	oldest, ok := dataMap[0]["ts"].(time.Time)
+	if !ok {
+		return nil
+	}
+	rows := toInt(dataMap[0]["cnt"], 0)
+
+	finder = zorm.NewFinder()
+	finder.Append(catalogSummarySelect(table)+" WHERE ts < ? PARTITION BY device_id,k", horizon)
+	dataMap, err = QueryMap(ctx, finder, nil)
+	if err != nil {
+		return err
+	}
+	if len(dataMap) >= rows {
+		return nil
+	}
+	entries := catalogEntries(dataMap)
+	if err := insertCatalog(entries, oldest.Add(-time.Duration(len(entries))*time.Microsecond)); err != nil {
+		return err
+	}
+
+	finder = zorm.NewFinder()
+	finder.Append(fmt.Sprintf("DELETE FROM %s WHERE ts >= ? AND ts < ?", table), oldest, horizon)
+	if _, err := zorm.UpdateFinder(ctx, finder); err != nil {
+		return err
+	}
+	logger.Db.Info("key catalog compacted", "table", catalogWriter.table, "rows", rows, "keys", len(entries))
+	return nil
+}
+
+// 按device_id、k汇总增量行，时间列转为微秒数比较
+func catalogSummarySelect(table string) string {
+	return fmt.Sprintf("SELECT device_id,k,MIN(CAST(first_ts AS BIGINT)) AS first_ts,MAX(CAST(last_ts AS BIGINT)) AS last_ts,SUM(point_count) AS point_count,LAST(value_type) AS value_type,LAST(tenant_id) AS tenant_id FROM %s", table)
+}
+
+func catalogEntries(dataMap []map[string]interface{}) []*CatalogEntry {
+	entries := make([]*CatalogEntry, 0, len(dataMap))
+	for _, mp := range dataMap {
+		entries = append(entries, &CatalogEntry{
+			DeviceId:  fmt.Sprintf("%v", mp["device_id"]),
+			Key:       fmt.Sprintf("%v", mp["k"]),
+			ValueType: toString(mp["value_type"], ""),
+			FirstTs:   time.UnixMicro(int64(toInt(mp["first_ts"], 0))),
+			LastTs:    time.UnixMicro(int64(toInt(mp["last_ts"], 0))),
+			Count:     int64(toInt(mp["point_count"], 0)),
+			TenantId:  toString(mp["tenant_id"], ""),
+		})
+	}
+	return entries
+}
+
+// 汇总各副本已持久化的目录
+func queryCatalogRows(ctx context.Context, deviceIds []string) (map[string][]*CatalogEntry, error) {
+	ret := make(map[string][]*CatalogEntry)
+	for i := 0; i < len(deviceIds); i += catalogQueryBatch {
+		j := i + catalogQueryBatch
+		if j > len(deviceIds) {
+			j = len(deviceIds)
+		}
+		finder := zorm.NewFinder()
+		finder.Append(catalogSummarySelect(DBName+"."+SuperTableTvCatalog)+" WHERE device_id in (?)", deviceIds[i:j])
+		finder.Append("PARTITION BY device_id,k")
+		dataMap, err := QueryMap(ctx, finder, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range catalogEntries(dataMap) {
+			ret[e.DeviceId] = append(ret[e.DeviceId], e)
+		}
+	}
+	return ret, nil
+}
+
+// 查询设备的key目录，合并本进程尚未持久化的增量
+func GetCatalog(ctx context.Context, deviceIds []string) ([]CatalogEntry, error) {
+	persisted, err := queryCatalogRows(ctx, deviceIds)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []CatalogEntry
+	catalogPending.mu.Lock()
+	defer catalogPending.mu.Unlock()
+	for _, id := range deviceIds {
+		keys := make(map[string]*CatalogEntry, len(persisted[id]))
+		for _, e := range persisted[id] {
+			keys[e.Key] = e
+		}
+		for _, d := range catalogPending.deltas[id] {
+			mergeCatalog(keys, d)
+		}
+		for _, e := range keys {
+			ret = append(ret, *e)
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMergeCatalog(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+	cur := CatalogEntry{Key: "temp", ValueType: "number", FirstTs: at(10), LastTs: at(20), Count: 5, TenantId: "t1"}
+
+	tests := []struct {
+		name  string
+		delta CatalogEntry
+		want  CatalogEntry
+	}{
+		{
+			name:  "newer delta updates last and type",
+			delta: CatalogEntry{Key: "temp", ValueType: "string", FirstTs: at(30), LastTs: at(40), Count: 2, TenantId: "t2"},
+			want:  CatalogEntry{Key: "temp", ValueType: "string", FirstTs: at(10), LastTs: at(40), Count: 7, TenantId: "t2"},
+		},
+		{
+			name:  "older delta only extends first",
+			delta: CatalogEntry{Key: "temp", ValueType: "string", FirstTs: at(1), LastTs: at(5), Count: 3, TenantId: "t2"},
+			want:  CatalogEntry{Key: "temp", ValueType: "number", FirstTs: at(1), LastTs: at(20), Count: 8, TenantId: "t1"},
+		},
+		{
+			name:  "overlapping delta",
+			delta: CatalogEntry{Key: "temp", ValueType: "number", FirstTs: at(15), LastTs: at(18), Count: 1},
+			want:  CatalogEntry{Key: "temp", ValueType: "number", FirstTs: at(10), LastTs: at(20), Count: 6, TenantId: "t1"},
+		},
+		{
+			// 最后一个值为占位值时类型未知，保留原类型和租户
+			name:  "same last ts keeps known type",
+			delta: CatalogEntry{Key: "temp", FirstTs: at(20), LastTs: at(20), Count: 1},
+			want:  CatalogEntry{Key: "temp", ValueType: "number", FirstTs: at(10), LastTs: at(20), Count: 6, TenantId: "t1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cur
+			keys := map[string]*CatalogEntry{"temp": &c}
+			d := tt.delta
+			mergeCatalog(keys, &d)
+			if *keys["temp"] != tt.want {
+				t.Fatalf("got %+v, want %+v", *keys["temp"], tt.want)
+			}
+			if d != tt.delta {
+				t.Fatal("delta modified")
+			}
+		})
+	}
+}
+
+func TestMergeCatalogNewKeyCopies(t *testing.T) {
+	keys := make(map[string]*CatalogEntry)
+	d := &CatalogEntry{Key: "temp", Count: 1}
+	mergeCatalog(keys, d)
+	mergeCatalog(keys, &CatalogEntry{Key: "temp", Count: 2})
+	if keys["temp"] == d || d.Count != 1 {
+		t.Fatal("first delta shared with the merged entry")
+	}
+	if keys["temp"].Count != 3 {
+		t.Fatalf("count = %d, want 3", keys["temp"].Count)
+	}
+}
+
+func TestNextCatalogTs(t *testing.T) {
+	// 同一子表内连续分配的时间戳不重叠
+	first := nextCatalogTs(1000)
+	second := nextCatalogTs(1)
+	if second.Before(first.Add(1000 * time.Microsecond)) {
+		t.Fatalf("second batch at %v overlaps first batch starting at %v", second, first)
+	}
+	if first.Truncate(time.Microsecond) != first {
+		t.Fatalf("%v is not in microseconds", first)
+	}
+}
@@ -2,16 +2,23 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/metrics"
+	"thingspanel-TDengine/tracing"
+
 	"gitee.com/chunanyong/zorm"
 	"github.com/spf13/viper"
 	_ "github.com/taosdata/driver-go/v3/taosRestful"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var Ch chan struct{}
@@ -43,6 +50,8 @@ const (
 	BoolDefault   = -1
 	DBName        = "things"
 	SuperTableTv  = "ts_kv"
+	// 最新值表
+	SuperTableTvLatest = "ts_kv_latest"
 )
 
 var dbDao *zorm.DBDao
@@ -50,9 +59,10 @@ var ctx = context.Background()
 
 func InitDb() {
 	if err := InitTd(); err != nil {
-		log.Fatalf("Failed to initialize db: %v", err)
+		logger.Db.Error("failed to initialize db", "error", err)
+		os.Exit(1)
 	}
-	log.Println("init db success")
+	logger.Db.Info("init db success")
 }
 
 func InitTd() error {
@@ -86,11 +96,62 @@ func InitTd() error {
 	var err error
 	dbDao, err = zorm.NewDBDao(&dbDaoConfig)
 	if err != nil {
-		log.Printf("%+v\n", err)
+		logger.Db.Error("failed to create db dao", "error", err)
 		return err
 	}
 
-	return createTdStable()
+	if err := createTdStable(); err != nil {
+		return err
+	}
+
+	if err := initLatest(); err != nil {
+		return err
+	}
+	return initCatalog()
+}
+
+// 查询超时
+var ErrQueryTimeout = errors.New("query timeout")
+
+// 查询超时时间：调用方有deadline(如gRPC deadline)时沿用，否则使用query.timeout
+func queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	timeout := viper.GetDuration("query.timeout") * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// 执行查询并记录耗时和span(SQL不含参数值)
+func QueryMap(ctx context.Context, finder *zorm.Finder, page *zorm.Page) ([]map[string]interface{}, error) {
+	ctx, cancel := queryContext(ctx)
+	defer cancel()
+	sqlstr, _ := finder.GetSQL()
+	ctx, span := tracing.Start(ctx, "db.query", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(tracing.DbAttributes(sqlstr)...))
+	start := time.Now()
+	dataMap, err := zorm.QueryMap(ctx, finder, page)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w: %v", ErrQueryTimeout, err)
+	}
+	span.SetAttributes(attribute.Int("db.rows", len(dataMap)))
+	tracing.End(span, err)
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	metrics.QueryDuration.WithLabelValues(status).Observe(time.Since(start).Seconds())
+	return dataMap, err
+}
+
+// 检查数据库是否可访问
+func Ping() error {
+	finder := zorm.NewFinder()
+	finder.Append("SELECT SERVER_STATUS()")
+	_, err := QueryMap(ctx, finder, nil)
+	return err
 }
 
 func createTdStable() error {
@@ -100,7 +161,7 @@ func createTdStable() error {
 	finder.Append(fmt.Sprintf("create database if not exists %s precision ?  keep ?", DBName), "us", 365*2)
 	_, err = zorm.UpdateFinder(ctx, finder)
 	if err != nil {
-		log.Printf("failed to create database, err:%+v\n", err)
+		logger.Db.Error("failed to create database", "error", err)
 		return err
 	}
 
@@ -110,7 +171,7 @@ func createTdStable() error {
 	finder.Append(sql)
 	_, err = zorm.UpdateFinder(ctx, finder)
 	if err != nil {
-		log.Printf("failed to create database, err:%+v\n", err)
+		logger.Db.Error("failed to create stable", "stable", SuperTableTv, "error", err)
 		return err
 	}
 
@@ -126,7 +187,7 @@ func createSubTables() error {
 		finder.Append(fmt.Sprintf(`create table if not exists %s.%s using %s.%s TAGS(?,?) `, DBName, fmt.Sprintf("%s00%d", SuperTableTv, i), DBName, SuperTableTv), fmt.Sprintf("00%d", i), "device")
 		_, err := zorm.UpdateFinder(ctx, finder)
 		if err != nil {
-			log.Printf("failed to create SuperTableBigint, err: %v", err)
+			logger.Db.Error("failed to create subtable", "error", err)
 			return err
 		}
 	}
@@ -134,32 +195,79 @@ func createSubTables() error {
 	return nil
 }
 
+// 本进程已创建过的子表，stable.tname
+var createdTables sync.Map
+
 // 创建子表
 // Create tables
-func createSubTablesByName(tname, device_id string) error {
+func createSubTablesByName(stable, tname, device_id string) error {
 	finder := zorm.NewFinder()
-	finder.Append(fmt.Sprintf(`create table if not exists %s.%s using %s.%s TAGS(?,?) `, DBName, tname, DBName, SuperTableTv), device_id, "device")
+	finder.Append(fmt.Sprintf(`create table if not exists %s.%s using %s.%s TAGS(?,?) `, DBName, tname, DBName, stable), device_id, "device")
 	_, err := zorm.UpdateFinder(ctx, finder)
 	if err != nil {
-		log.Printf("failed to create createSubTablesByName err: %v", err)
+		logger.Db.Error("failed to create subtable", "table", tname, "device_id", device_id, "error", err)
 		return err
 	}
+	// 只统计本进程第一次创建的子表
+	if _, loaded := createdTables.LoadOrStore(stable+"."+tname, true); !loaded {
+		metrics.SubtableCreates.Inc()
+	}
 
 	return nil
 }
 
+// 写入通道的一条设备消息，设备和租户、span上下文与设备上报的值分开传递，上报的值不能覆盖
+type Message struct {
+	DeviceId    string
+	TenantId    string
+	Values      map[string]interface{}
+	SpanContext trace.SpanContext // 接收消息的span，flush时作为链接
+}
+
+// 写入行使用的字段名，设备上报的值不能使用这些key
+var ReservedKeys = []string{"device_id", "tenant_id"}
+
+// 把一条消息拆成每个key一行，device_id和tenant_id只取自消息本身
+func messageRows(m Message) []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, len(m.Values))
+	for key, value := range m.Values {
+		info := map[string]interface{}{
+			"device_id": m.DeviceId,
+			"key":       key,
+			"value":     value,
+			"ts":        time.Now().Nanosecond(),
+		}
+		if m.TenantId != "" {
+			info["tenant_id"] = m.TenantId
+		}
+		rows = append(rows, info)
+	}
+	return rows
+}
+
 type Worker struct {
-	Tc *time.Ticker
+	Tc    *time.Ticker
+	links []trace.Link // 当前批次各消息的span，flush时作为链接
 }
 
 func (w *Worker) DoInsertBatch(bathlist []map[string]interface{}) {
+	start := time.Now()
+	ctx, span := tracing.Start(context.Background(), "db.flush", trace.WithAttributes(attribute.Int("batch.messages", len(bathlist))),
+		trace.WithLinks(w.links...))
+	w.links = w.links[:0]
+	defer func() {
+		metrics.FlushLatency.Observe(time.Since(start).Seconds())
+		span.End()
+	}()
+
 	var err error
 	var buff strings.Builder
 	var demos []zorm.IEntityStruct
 	for i := 0; i < len(bathlist); i++ {
 		message := bathlist[i]
 		if _, ok := message["device_id"]; !ok {
-			log.Printf("device_id not exist in message:%v\n", message)
+			logger.Db.Warn("device_id not exist in message")
+			logger.Db.Debug("message without device_id", "message", message)
 			continue
 		}
 
@@ -174,12 +282,17 @@ func (w *Worker) DoInsertBatch(bathlist []map[string]interface{}) {
 		tablename := buff.String()
 		buff.Reset()
 
-		err = createSubTablesByName(tablename, deviceId)
+		err = createSubTablesByName(SuperTableTv, tablename, deviceId)
 		if err != nil {
-			log.Printf("createSubTablesByName err:%v\n", err)
+			metrics.RowsFailed.Inc()
 			continue
 		}
 
+		var tenantId string
+		if v, ok := message["tenant_id"]; ok {
+			tenantId = fmt.Sprintf("%v", v)
+		}
+
 		if _, ok := message["device_id"]; ok {
 			if value, ok := message["value"].(string); ok {
 				demo1 := Demo{Ts: time.Now(),
@@ -188,6 +301,7 @@ func (w *Worker) DoInsertBatch(bathlist []map[string]interface{}) {
 					StringV:   value,
 					NumberV:   NumberDefault,
 					BoolV:     -1,
+					TenantId:  tenantId,
 					TableName: DBName + "." + tablename}
 				demos = append(demos, &demo1)
 			} else if f, ok := message["value"].(float64); ok {
@@ -197,6 +311,7 @@ func (w *Worker) DoInsertBatch(bathlist []map[string]interface{}) {
 					NumberV:   f,
 					StringV:   StringDefault,
 					BoolV:     -1,
+					TenantId:  tenantId,
 					TableName: DBName + "." + tablename}
 				demos = append(demos, &demo2)
 			} else if b, ok := message["value"].(bool); ok {
@@ -210,10 +325,12 @@ func (w *Worker) DoInsertBatch(bathlist []map[string]interface{}) {
 					BoolV:     bv,
 					NumberV:   NumberDefault,
 					StringV:   StringDefault,
+					TenantId:  tenantId,
 					TableName: DBName + "." + tablename}
 				demos = append(demos, &demo2)
 			} else {
-				log.Printf("err type value:%v\n", message["device_id"])
+				logger.Db.Warn("unsupported value type", "device_id", deviceId, "tenant_id", tenantId, "key", message["key"])
+				metrics.RowsFailed.Inc()
 				continue
 			}
 		}
@@ -222,22 +339,30 @@ func (w *Worker) DoInsertBatch(bathlist []map[string]interface{}) {
 	if len(demos) > 0 {
 		// //相同结构的的子表（同一超级表下子表,如果不是必须保证类型一致）
 		//tableName 是可以替换的 demo定义的是超级表结构
-		num, err := zorm.InsertSlice(context.Background(), demos)
+		metrics.BatchSize.Observe(float64(len(demos)))
+		insertCtx, insertSpan := tracing.Start(ctx, "db.insert", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attribute.Int("db.rows", len(demos))))
+		num, err := zorm.InsertSlice(insertCtx, demos)
+		tracing.End(insertSpan, err)
 		if err != nil {
-			log.Printf("err:%v\n", err)
+			logger.Db.Error("failed to insert batch", "rows", len(demos), "error", err)
+			metrics.RowsFailed.Add(float64(len(demos)))
+		} else {
+			updateLatest(demos)
+			updateCatalog(demos)
+			metrics.RowsInserted.Add(float64(num))
 		}
 
 		atomic.AddInt64(&Num, int64(num))
 	}
 }
 
-func (w *Worker) Bulk_inset_struct(wg *sync.WaitGroup, ctx context.Context, messages chan map[string]interface{}) {
+func (w *Worker) Bulk_inset_struct(wg *sync.WaitGroup, ctx context.Context, messages chan Message) {
 	defer wg.Done()
 
 	batchWaitTime := viper.GetDuration("db.batch_wait_time") * time.Second
 	batchSize := viper.GetInt("db.batch_size")
 
-	log.Printf("batchSize: %+v batchWaitTime: %+v\n", batchSize, batchWaitTime)
+	logger.Db.Info("write worker started", "batch_size", batchSize, "batch_wait_time", batchWaitTime)
 
 	w.Tc = time.NewTicker(1 * time.Second)
 	defer w.Tc.Stop()
@@ -258,23 +383,13 @@ func (w *Worker) Bulk_inset_struct(wg *sync.WaitGroup, ctx context.Context, mess
 				return
 			}
 
-			if _, ok := message["device_id"]; !ok {
+			if message.DeviceId == "" {
 				continue
 			}
-
-			deviceid := message["device_id"]
-			delete(message, "device_id")
-
-			for key, value := range message {
-				info := map[string]interface{}{
-					"device_id": deviceid,
-					"key":       key,
-					"value":     value,
-					"ts":        time.Now().Nanosecond(),
-				}
-
-				bathlist = append(bathlist, info)
+			if message.SpanContext.IsValid() {
+				w.links = append(w.links, trace.Link{SpanContext: message.SpanContext})
 			}
+			bathlist = append(bathlist, messageRows(message)...)
 
 			if (len(bathlist) >= batchSize) || (time.Since(time.Now()) >= batchWaitTime && len(bathlist) > 0) {
 				w.DoInsertBatch(bathlist)
@@ -0,0 +1,27 @@
+package db
+
+import "testing"
+
+func TestMessageRows(t *testing.T) {
+	// 上报的值中带tenant_id时，写入行的租户仍取自消息
+	m := Message{DeviceId: "d1", TenantId: "t1", Values: map[string]interface{}{"temp": 21.5, "tenant_id": "other", "device_id": "d2"}}
+	rows := messageRows(m)
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	for _, row := range rows {
+		if row["device_id"] != "d1" || row["tenant_id"] != "t1" {
+			t.Fatalf("row %v: device_id/tenant_id taken from values", row)
+		}
+	}
+}
+
+func TestMessageRowsWithoutTenant(t *testing.T) {
+	rows := messageRows(Message{DeviceId: "d1", Values: map[string]interface{}{"tenant_id": "other"}})
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1", len(rows))
+	}
+	if v, ok := rows[0]["tenant_id"]; ok {
+		t.Fatalf("tenant_id = %v, want none", v)
+	}
+}
@@ -0,0 +1,652 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"thingspanel-TDengine/logger"
+
+	"gitee.com/chunanyong/zorm"
+	"github.com/spf13/viper"
+)
+
+// 设备某个key的最新值
+type LatestValue struct {
+	Ts       time.Time
+	Key      string
+	BoolV    int
+	NumberV  float64
+	StringV  string
+	TenantId string
+}
+
+// 转成与ts_kv查询结果一致的格式
+func (v LatestValue) Map() map[string]interface{} {
+	return map[string]interface{}{
+		"ts":        v.Ts,
+		"k":         v.Key,
+		"bool_v":    v.BoolV,
+		"number_v":  v.NumberV,
+		"string_v":  v.StringV,
+		"tenant_id": v.TenantId,
+	}
+}
+
+type latestEntry struct {
+	deviceId string
+	values   map[string]LatestValue
+	complete bool      // 是否已包含设备的全部key(从数据库加载过)
+	checked  time.Time // 最近一次与ts_kv核对的时间
+}
+
+// ts_kv_latest的一行，子表按设备和key划分，每个子表只有latestRowTs一行，持久化时覆盖
+type latestRow struct {
+	zorm.EntityStruct
+	Ts        time.Time `column:"ts"`
+	ValueTs   time.Time `column:"value_ts"`
+	DeviceId  string    `column:"device_id"`
+	K         string    `column:"k"`
+	BoolV     int       `column:"bool_v"`
+	NumberV   float64   `column:"number_v"`
+	StringV   string    `column:"string_v"`
+	TenantId  string    `column:"tenant_id"`
+	TableName string
+}
+
+func (entity *latestRow) GetTableName() string {
+	return entity.TableName
+}
+
+func (entity *latestRow) GetPKColumnName() string {
+	return ""
+}
+
+// ts_kv_latest所在的库：固定时间戳的行需要不过期，不能放在有keep的things库中
+const (
+	LatestDBName   = DBName + "_latest"
+	latestKeepDays = 365000
+)
+
+// ts_kv_latest所有行的时间戳，值的实际时间在value_ts列
+var latestRowTs = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// 本进程已创建过的ts_kv_latest子表
+var latestTables sync.Map
+
+// 最新值缓存，按设备LRU淘汰，定时持久化到ts_kv_latest
+type latestCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+	dirty map[string]map[string]LatestValue // 待持久化的最新值
+}
+
+var latest = &latestCache{
+	ll:    list.New(),
+	items: make(map[string]*list.Element),
+	dirty: make(map[string]map[string]LatestValue),
+}
+
+// 初始化最新值缓存：建表、预热、启动定时持久化
+func initLatest() error {
+	latest.size = viper.GetInt("db.latest_cache_size")
+	if latest.size <= 0 {
+		latest.size = 10000
+	}
+
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("create database if not exists %s precision ? keep ?", LatestDBName), "us", latestKeepDays)
+	_, err := zorm.UpdateFinder(ctx, finder)
+	if err != nil {
+		logger.Db.Error("failed to create database", "database", LatestDBName, "error", err)
+		return err
+	}
+
+	finder = zorm.NewFinder()
+	finder.Append(fmt.Sprintf("CREATE STABLE if not exists %s.%s (ts TIMESTAMP, value_ts TIMESTAMP, device_id NCHAR(64), k NCHAR(64), bool_v TINYINT, number_v DOUBLE, string_v NCHAR(256), tenant_id NCHAR(64)) TAGS (model_id BINARY(64), model_name BINARY(64))",
+		LatestDBName, SuperTableTvLatest))
+	_, err = zorm.UpdateFinder(ctx, finder)
+	if err != nil {
+		logger.Db.Error("failed to create stable", "stable", SuperTableTvLatest, "error", err)
+		return err
+	}
+
+	if err := warmLatest(); err != nil {
+		// 预热失败不影响服务，缓存未命中时回源查询
+		logger.Db.Warn("failed to warm latest cache", "error", err)
+	}
+
+	go flushLatestLoop()
+	return nil
+}
+
+// 缓存核对周期：其他副本写入的数据不会更新本地缓存，超过该时间的设备读取前与ts_kv的LAST_ROW(ts)核对
+func latestTTL() time.Duration {
+	ttl := viper.GetDuration("db.latest_cache_ttl") * time.Second
+	if ttl <= 0 {
+		ttl = 5 * time.Second
+	}
+	return ttl
+}
+
+func flushInterval() time.Duration {
+	interval := viper.GetDuration("db.latest_flush_interval") * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return interval
+}
+
+// 冷启动预热：从ts_kv_latest加载，再从ts_kv补齐最后一次持久化之后写入的数据
+// ts_kv_latest为空(首次部署)时从ts_kv全量加载一次
+func warmLatest() error {
+	// 预热需要扫描全部设备，不使用默认查询超时
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	values, err := queryLatestTable(ctx)
+	if err != nil {
+		return err
+	}
+
+	seed := len(values) == 0
+	var since time.Time
+	for _, vs := range values {
+		for _, v := range vs {
+			if v.Ts.After(since) {
+				since = v.Ts
+			}
+		}
+	}
+	if !seed {
+		since = since.Add(-2 * flushInterval())
+	}
+
+	recent, err := queryLatestRows(ctx, SuperTableTv, nil, nil, since)
+	if err != nil {
+		return err
+	}
+
+	latest.mu.Lock()
+	defer latest.mu.Unlock()
+	for deviceId, vs := range values {
+		latest.merge(deviceId, vs, true)
+	}
+	for deviceId, vs := range recent {
+		latest.merge(deviceId, vs, true)
+		for _, v := range vs {
+			latest.markDirty(deviceId, v)
+		}
+	}
+	logger.Db.Info("latest cache warmed", "devices", latest.ll.Len())
+	return nil
+}
+
+// 写入成功后更新最新值
+func updateLatest(demos []zorm.IEntityStruct) {
+	values := make(map[string][]LatestValue)
+	for _, e := range demos {
+		demo, ok := e.(*Demo)
+		if !ok {
+			continue
+		}
+		values[demo.DeviceId] = append(values[demo.DeviceId], LatestValue{
+			Ts:       demo.Ts,
+			Key:      demo.K,
+			BoolV:    demo.BoolV,
+			NumberV:  demo.NumberV,
+			StringV:  demo.StringV,
+			TenantId: demo.TenantId,
+		})
+	}
+
+	latest.mu.Lock()
+	defer latest.mu.Unlock()
+	for deviceId, vs := range values {
+		latest.merge(deviceId, vs, false)
+		for _, v := range vs {
+			latest.markDirty(deviceId, v)
+		}
+	}
+}
+
+// 查询多个设备各key的最新值，keys为空时返回全部key
+// 缓存未命中的设备通过一次LAST_ROW查询回源并写入缓存
+func GetLatest(ctx context.Context, deviceIds []string, keys []string) (map[string][]LatestValue, error) {
+	var keyList []string
+	for _, k := range keys {
+		if k != "" {
+			keyList = append(keyList, k)
+		}
+	}
+
+	ret := make(map[string][]LatestValue, len(deviceIds))
+	var missed, stale []string
+	ttl := latestTTL()
+
+	latest.mu.Lock()
+	for _, deviceId := range deviceIds {
+		vs, ok := latest.get(deviceId, keyList)
+		if !ok {
+			missed = append(missed, deviceId)
+			continue
+		}
+		if latest.expired(deviceId, ttl) {
+			stale = append(stale, deviceId)
+			continue
+		}
+		ret[deviceId] = vs
+	}
+	latest.mu.Unlock()
+
+	if len(stale) > 0 {
+		if err := revalidateLatest(ctx, stale); err != nil {
+			return nil, err
+		}
+		latest.mu.Lock()
+		for _, deviceId := range stale {
+			vs, ok := latest.get(deviceId, keyList)
+			if !ok {
+				// 核对期间被淘汰
+				missed = append(missed, deviceId)
+				continue
+			}
+			ret[deviceId] = vs
+		}
+		latest.mu.Unlock()
+	}
+
+	if len(missed) == 0 {
+		return ret, nil
+	}
+
+	loaded, err := queryLatestRows(ctx, SuperTableTv, missed, nil, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	latest.mu.Lock()
+	defer latest.mu.Unlock()
+	for _, deviceId := range missed {
+		latest.merge(deviceId, loaded[deviceId], true)
+		ret[deviceId], _ = latest.get(deviceId, keyList)
+	}
+	return ret, nil
+}
+
+// 与ts_kv核对设备的最后写入时间，有更新(如写入落在其他副本)时补查之后的最新值
+func revalidateLatest(ctx context.Context, deviceIds []string) error {
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT device_id,LAST_ROW(ts) AS ts FROM %s.%s WHERE device_id in (?) PARTITION BY device_id",
+		DBName, SuperTableTv), deviceIds)
+	dataMap, err := QueryMap(ctx, finder, nil)
+	if err != nil {
+		return err
+	}
+	lastWrite := make(map[string]time.Time, len(dataMap))
+	for _, mp := range dataMap {
+		if ts, ok := mp["ts"].(time.Time); ok {
+			lastWrite[fmt.Sprintf("%v", mp["device_id"])] = ts
+		}
+	}
+
+	var changed []string
+	var since time.Time
+	latest.mu.Lock()
+	for _, deviceId := range deviceIds {
+		cached := latest.lastTs(deviceId)
+		if lastWrite[deviceId].After(cached) {
+			changed = append(changed, deviceId)
+			if since.IsZero() || cached.Before(since) {
+				since = cached
+			}
+		}
+	}
+	latest.mu.Unlock()
+
+	var loaded map[string][]LatestValue
+	if len(changed) > 0 {
+		if loaded, err = queryLatestRows(ctx, SuperTableTv, changed, nil, since); err != nil {
+			return err
+		}
+	}
+
+	latest.mu.Lock()
+	defer latest.mu.Unlock()
+	for _, deviceId := range deviceIds {
+		if _, ok := latest.items[deviceId]; ok {
+			latest.merge(deviceId, loaded[deviceId], true)
+		}
+	}
+	return nil
+}
+
+// 设备所属租户，取自最新值中的tenant_id，一次读取全部设备，未上报过租户的设备为空字符串
+func DeviceTenants(ctx context.Context, deviceIds []string) (map[string]string, error) {
+	values, err := GetLatest(ctx, deviceIds, nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]string, len(deviceIds))
+	for _, deviceId := range deviceIds {
+		ret[deviceId] = latestTenant(values[deviceId])
+	}
+	return ret, nil
+}
+
+// 最后一次上报的非空租户
+func latestTenant(vs []LatestValue) string {
+	var tenant string
+	var ts time.Time
+	for _, v := range vs {
+		if v.TenantId != "" && v.Ts.After(ts) {
+			tenant, ts = v.TenantId, v.Ts
+		}
+	}
+	return tenant
+}
+
+// 调用方需持有锁
+func (c *latestCache) get(deviceId string, keys []string) ([]LatestValue, bool) {
+	el, ok := c.items[deviceId]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	entry := el.Value.(*latestEntry)
+
+	var vs []LatestValue
+	if len(keys) == 0 {
+		if !entry.complete {
+			return nil, false
+		}
+		for _, v := range entry.values {
+			vs = append(vs, v)
+		}
+		return vs, true
+	}
+
+	for _, k := range keys {
+		v, ok := entry.values[k]
+		if !ok {
+			if !entry.complete {
+				return nil, false
+			}
+			continue
+		}
+		vs = append(vs, v)
+	}
+	return vs, true
+}
+
+// 完整缓存是否超过核对周期；调用方需持有锁
+func (c *latestCache) expired(deviceId string, ttl time.Duration) bool {
+	el, ok := c.items[deviceId]
+	if !ok {
+		return false
+	}
+	entry := el.Value.(*latestEntry)
+	return entry.complete && time.Since(entry.checked) > ttl
+}
+
+// 缓存中设备最新一条数据的时间；调用方需持有锁
+func (c *latestCache) lastTs(deviceId string) time.Time {
+	var ts time.Time
+	if el, ok := c.items[deviceId]; ok {
+		for _, v := range el.Value.(*latestEntry).values {
+			if v.Ts.After(ts) {
+				ts = v.Ts
+			}
+		}
+	}
+	return ts
+}
+
+// 合并最新值，只保留时间更新的值；complete表示从数据库完整加载或核对过；调用方需持有锁
+func (c *latestCache) merge(deviceId string, vs []LatestValue, complete bool) {
+	el, ok := c.items[deviceId]
+	if !ok {
+		el = c.ll.PushFront(&latestEntry{deviceId: deviceId, values: make(map[string]LatestValue)})
+		c.items[deviceId] = el
+		for c.ll.Len() > c.size {
+			oldest := c.ll.Back()
+			c.ll.Remove(oldest)
+			delete(c.items, oldest.Value.(*latestEntry).deviceId)
+		}
+	} else {
+		c.ll.MoveToFront(el)
+	}
+
+	entry := el.Value.(*latestEntry)
+	for _, v := range vs {
+		if old, ok := entry.values[v.Key]; ok && old.Ts.After(v.Ts) {
+			continue
+		}
+		entry.values[v.Key] = v
+	}
+	if complete {
+		entry.complete = true
+		entry.checked = time.Now()
+	}
+}
+
+// 调用方需持有锁
+func (c *latestCache) markDirty(deviceId string, v LatestValue) {
+	m, ok := c.dirty[deviceId]
+	if !ok {
+		m = make(map[string]LatestValue)
+		c.dirty[deviceId] = m
+	}
+	if old, ok := m[v.Key]; ok && old.Ts.After(v.Ts) {
+		return
+	}
+	m[v.Key] = v
+}
+
+func flushLatestLoop() {
+	tc := time.NewTicker(flushInterval())
+	defer tc.Stop()
+	for range tc.C {
+		flushLatest()
+	}
+}
+
+// 将待持久化的最新值覆盖写入ts_kv_latest，失败的值放回下次重试
+// 多个副本覆盖同一行时可能写回较旧的值，预热后读取前与ts_kv的核对会纠正
+func flushLatest() {
+	latest.mu.Lock()
+	dirty := latest.dirty
+	latest.dirty = make(map[string]map[string]LatestValue)
+	latest.mu.Unlock()
+
+	if len(dirty) == 0 {
+		return
+	}
+
+	failed := make(map[string]map[string]LatestValue)
+	var rows []zorm.IEntityStruct
+	for deviceId, vs := range dirty {
+		for key, v := range vs {
+			tablename, err := createLatestTable(deviceId, key)
+			if err != nil {
+				if failed[deviceId] == nil {
+					failed[deviceId] = make(map[string]LatestValue)
+				}
+				failed[deviceId][key] = v
+				continue
+			}
+			rows = append(rows, &latestRow{
+				Ts:        latestRowTs,
+				ValueTs:   v.Ts,
+				DeviceId:  deviceId,
+				K:         key,
+				BoolV:     v.BoolV,
+				NumberV:   v.NumberV,
+				StringV:   v.StringV,
+				TenantId:  v.TenantId,
+				TableName: LatestDBName + "." + tablename,
+			})
+		}
+	}
+
+	if len(rows) > 0 {
+		if _, err := zorm.InsertSlice(ctx, rows); err != nil {
+			logger.Db.Error("failed to flush latest values", "rows", len(rows), "error", err)
+			failed = dirty
+		}
+	}
+	if len(failed) > 0 {
+		latest.mu.Lock()
+		latest.restoreDirty(failed)
+		latest.mu.Unlock()
+	}
+}
+
+// 放回未持久化的值，期间又有更新的key保留较新的值；调用方需持有锁
+func (c *latestCache) restoreDirty(dirty map[string]map[string]LatestValue) {
+	for deviceId, vs := range dirty {
+		for _, v := range vs {
+			c.markDirty(deviceId, v)
+		}
+	}
+}
+
+// 设备和key对应的ts_kv_latest子表名
+func latestTableName(deviceId, key string) string {
+	h := fnv.New64a()
+	h.Write([]byte(deviceId + "\x00" + key))
+	return fmt.Sprintf("%s_%016x", SuperTableTvLatest, h.Sum64())
+}
+
+// 创建设备和key的ts_kv_latest子表，本进程已创建过的不再重复执行
+func createLatestTable(deviceId, key string) (string, error) {
+	tablename := latestTableName(deviceId, key)
+	if _, ok := latestTables.Load(tablename); ok {
+		return tablename, nil
+	}
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf(`create table if not exists %s.%s using %s.%s TAGS(?,?) `, LatestDBName, tablename, LatestDBName, SuperTableTvLatest), deviceId, "device")
+	if _, err := zorm.UpdateFinder(ctx, finder); err != nil {
+		logger.Db.Error("failed to create subtable", "table", tablename, "device_id", deviceId, "error", err)
+		return "", err
+	}
+	latestTables.Store(tablename, true)
+	return tablename, nil
+}
+
+// 读取ts_kv_latest的全部行，每个设备和key只有一行，不需要聚合
+func queryLatestTable(ctx context.Context) (map[string][]LatestValue, error) {
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT device_id,k,value_ts,bool_v,number_v,string_v,tenant_id FROM %s.%s", LatestDBName, SuperTableTvLatest))
+	dataMap, err := QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string][]LatestValue)
+	for _, mp := range dataMap {
+		ts, ok := mp["value_ts"].(time.Time)
+		if !ok {
+			continue
+		}
+		deviceId := fmt.Sprintf("%v", mp["device_id"])
+		ret[deviceId] = append(ret[deviceId], LatestValue{
+			Ts:       ts,
+			Key:      fmt.Sprintf("%v", mp["k"]),
+			BoolV:    toInt(mp["bool_v"], BoolDefault),
+			NumberV:  toFloat(mp["number_v"], NumberDefault),
+			StringV:  toString(mp["string_v"], StringDefault),
+			TenantId: toString(mp["tenant_id"], ""),
+		})
+	}
+	return ret, nil
+}
+
+// 按设备和key查询最新一行，deviceIds为空时查询全部设备，since不为零时只查询该时间之后的数据
+func queryLatestRows(ctx context.Context, table string, deviceIds []string, keys []string, since time.Time) (map[string][]LatestValue, error) {
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT device_id,k,LAST_ROW(ts) AS ts,LAST_ROW(bool_v) AS bool_v,LAST_ROW(number_v) AS number_v,LAST_ROW(string_v) AS string_v,LAST_ROW(tenant_id) AS tenant_id FROM %s.%s WHERE 1=1",
+		DBName, table))
+	if len(deviceIds) > 0 {
+		finder.Append("AND device_id in (?)", deviceIds)
+	}
+	if len(keys) > 0 {
+		finder.Append("AND k in (?)", keys)
+	}
+	if !since.IsZero() {
+		finder.Append("AND ts >= ?", since)
+	}
+	finder.Append("PARTITION BY device_id,k")
+
+	dataMap, err := QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string][]LatestValue)
+	for _, mp := range dataMap {
+		ts, ok := mp["ts"].(time.Time)
+		if !ok {
+			continue
+		}
+		deviceId := fmt.Sprintf("%v", mp["device_id"])
+		ret[deviceId] = append(ret[deviceId], LatestValue{
+			Ts:       ts,
+			Key:      fmt.Sprintf("%v", mp["k"]),
+			BoolV:    toInt(mp["bool_v"], BoolDefault),
+			NumberV:  toFloat(mp["number_v"], NumberDefault),
+			StringV:  toString(mp["string_v"], StringDefault),
+			TenantId: toString(mp["tenant_id"], ""),
+		})
+	}
+	return ret, nil
+}
+
+func toInt(v interface{}, def int) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int8:
+		return int(n)
+	case int16:
+		return int(n)
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case bool:
+		if n {
+			return 1
+		}
+		return 0
+	}
+	return def
+}
+
+func toFloat(v interface{}, def float64) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case float32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	}
+	return def
+}
+
+func toString(v interface{}, def string) string {
+	if v == nil {
+		return def
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"container/list"
+	"testing"
+	"time"
+)
+
+func newTestCache(size int) *latestCache {
+	return &latestCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		dirty: make(map[string]map[string]LatestValue),
+	}
+}
+
+func TestLatestCacheGet(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	tests := []struct {
+		name     string
+		complete bool
+		keys     []string
+		wantOk   bool
+		wantLen  int
+	}{
+		{name: "incomplete all keys misses", complete: false, keys: nil, wantOk: false},
+		{name: "incomplete known key hits", complete: false, keys: []string{"temp"}, wantOk: true, wantLen: 1},
+		{name: "incomplete unknown key misses", complete: false, keys: []string{"hum"}, wantOk: false},
+		{name: "complete all keys hits", complete: true, keys: nil, wantOk: true, wantLen: 2},
+		{name: "complete unknown key is empty", complete: true, keys: []string{"hum"}, wantOk: true, wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCache(10)
+			c.merge("d1", []LatestValue{{Key: "temp", Ts: t0}, {Key: "door", Ts: t0}}, tt.complete)
+			vs, ok := c.get("d1", tt.keys)
+			if ok != tt.wantOk || len(vs) != tt.wantLen {
+				t.Fatalf("get = %d values, %v; want %d, %v", len(vs), ok, tt.wantLen, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestLatestCacheMergeKeepsNewer(t *testing.T) {
+	c := newTestCache(10)
+	t0 := time.Unix(1000, 0)
+	c.merge("d1", []LatestValue{{Key: "temp", Ts: t0.Add(time.Second), NumberV: 2}}, false)
+	c.merge("d1", []LatestValue{{Key: "temp", Ts: t0, NumberV: 1}}, true)
+	vs, _ := c.get("d1", []string{"temp"})
+	if len(vs) != 1 || vs[0].NumberV != 2 {
+		t.Fatalf("got %+v, want the newer value 2", vs)
+	}
+	if got := c.lastTs("d1"); !got.Equal(t0.Add(time.Second)) {
+		t.Fatalf("lastTs = %v", got)
+	}
+}
+
+func TestLatestCacheEviction(t *testing.T) {
+	c := newTestCache(2)
+	for _, d := range []string{"d1", "d2", "d3"} {
+		c.merge(d, []LatestValue{{Key: "k", Ts: time.Unix(1, 0)}}, true)
+	}
+	if _, ok := c.items["d1"]; ok {
+		t.Fatal("least recently used device not evicted")
+	}
+	if c.ll.Len() != 2 {
+		t.Fatalf("len = %d, want 2", c.ll.Len())
+	}
+}
+
+func TestLatestCacheExpired(t *testing.T) {
+	c := newTestCache(10)
+	c.merge("incomplete", []LatestValue{{Key: "k", Ts: time.Unix(1, 0)}}, false)
+	c.merge("complete", []LatestValue{{Key: "k", Ts: time.Unix(1, 0)}}, true)
+
+	if c.expired("incomplete", 0) {
+		t.Fatal("incomplete entries are loaded on demand, not revalidated")
+	}
+	if c.expired("complete", time.Minute) {
+		t.Fatal("freshly checked entry reported expired")
+	}
+	c.items["complete"].Value.(*latestEntry).checked = time.Now().Add(-2 * time.Minute)
+	if !c.expired("complete", time.Minute) {
+		t.Fatal("entry older than ttl not expired")
+	}
+	if c.expired("missing", time.Minute) {
+		t.Fatal("missing entry reported expired")
+	}
+}
+
+func TestLatestCacheRestoreDirty(t *testing.T) {
+	c := newTestCache(10)
+	t0 := time.Unix(1000, 0)
+	// 持久化期间temp又有更新，放回时保留较新的值
+	c.markDirty("d1", LatestValue{Key: "temp", Ts: t0.Add(time.Second), NumberV: 2})
+	c.restoreDirty(map[string]map[string]LatestValue{
+		"d1": {"temp": {Key: "temp", Ts: t0, NumberV: 1}, "hum": {Key: "hum", Ts: t0, NumberV: 50}},
+		"d2": {"temp": {Key: "temp", Ts: t0, NumberV: 3}},
+	})
+	if got := c.dirty["d1"]["temp"].NumberV; got != 2 {
+		t.Fatalf("temp = %v, want the newer value 2", got)
+	}
+	if _, ok := c.dirty["d1"]["hum"]; !ok {
+		t.Fatal("hum not restored")
+	}
+	if _, ok := c.dirty["d2"]["temp"]; !ok {
+		t.Fatal("d2 not restored")
+	}
+}
+
+func TestLatestTableName(t *testing.T) {
+	a := latestTableName("d1", "temp")
+	if a != latestTableName("d1", "temp") {
+		t.Fatal("table name not stable")
+	}
+	for _, other := range []string{latestTableName("d1", "hum"), latestTableName("d2", "temp"), latestTableName("d1t", "emp")} {
+		if other == a {
+			t.Fatalf("%s shared by different device and key", a)
+		}
+	}
+}
+
+func TestLatestTenant(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	tests := []struct {
+		name string
+		vs   []LatestValue
+		want string
+	}{
+		{name: "no values", want: ""},
+		{name: "never reported tenant", vs: []LatestValue{{Key: "temp", Ts: t0}}, want: ""},
+		{
+			name: "latest non-empty tenant",
+			vs: []LatestValue{
+				{Key: "temp", Ts: t0, TenantId: "t1"},
+				{Key: "hum", Ts: t0.Add(time.Second), TenantId: "t2"},
+				{Key: "door", Ts: t0.Add(2 * time.Second)},
+			},
+			want: "t2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := latestTenant(tt.vs); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,24 +21,65 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
+	"fmt"
 	"log"
+	"os"
 	"time"
 
 	pb "c/grpc_tptodb"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
-	addr = flag.String("addr", "127.0.0.1:50052", "the address to connect to")
+	addr       = flag.String("addr", "127.0.0.1:50052", "the address to connect to")
+	useTLS     = flag.Bool("tls", false, "connect using TLS")
+	caFile     = flag.String("ca_file", "", "CA certificate used to verify the server, system roots if empty")
+	certFile   = flag.String("cert_file", "", "client certificate for mTLS")
+	keyFile    = flag.String("key_file", "", "client private key for mTLS")
+	serverName = flag.String("server_name", "", "server name used to verify the certificate, host of addr if empty")
 )
 
+// 根据命令行参数生成传输层凭证
+func transportCredentials() (credentials.TransportCredentials, error) {
+	if !*useTLS {
+		return insecure.NewCredentials(), nil
+	}
+
+	cfg := &tls.Config{ServerName: *serverName, MinVersion: tls.VersionTLS12}
+	if *caFile != "" {
+		pem, err := os.ReadFile(*caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in ca file %s", *caFile)
+		}
+	}
+	if *certFile != "" && *keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
 func main() {
 	flag.Parse()
+	creds, err := transportCredentials()
+	if err != nil {
+		log.Fatalf("failed to load credentials: %v", err)
+	}
 	// Set up a connection to the server.
-	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(*addr, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
 	}
@@ -0,0 +1,156 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 允许的聚合函数，函数名和参数经校验后才拼入SQL
+type aggregateFunc struct {
+	expr     string  // SQL表达式，%s为参数
+	param    bool    // 是否需要参数，如percentile(95)
+	paramMin float64 // 参数范围
+	paramMax float64
+	single   bool   // 只能在单表上计算，查询需PARTITION BY tbname，不能跨设备分组
+	instead  string // 跨设备分组时建议改用的函数
+}
+
+var aggregateFuncs = map[string]aggregateFunc{
+	"avg":         {expr: "AVG(number_v)"},
+	"min":         {expr: "MIN(number_v)"},
+	"max":         {expr: "MAX(number_v)"},
+	"sum":         {expr: "SUM(number_v)"},
+	"count":       {expr: "COUNT(number_v)"},
+	"first":       {expr: "FIRST(number_v)"},
+	"last":        {expr: "LAST(number_v)"},
+	"spread":      {expr: "SPREAD(number_v)"},
+	"stddev":      {expr: "STDDEV(number_v)"},
+	"twa":         {expr: "TWA(number_v)"},
+	"mode":        {expr: "MODE(number_v)"},
+	"elapsed":     {expr: "ELAPSED(ts, 1a)", single: true}, // 窗口内首末数据的时间差，单位毫秒
+	"percentile":  {expr: "PERCENTILE(number_v, %s)", param: true, paramMin: 0, paramMax: 100, single: true, instead: "apercentile"},
+	"apercentile": {expr: "APERCENTILE(number_v, %s)", param: true, paramMin: 0, paramMax: 100},
+}
+
+// 单次请求最多的聚合函数个数
+const maxAggregateFuncs = 10
+
+var aggregateFuncPattern = regexp.MustCompile(`^([a-z_]+)\s*(?:\(\s*([0-9]+(?:\.[0-9]+)?)\s*\))?$`)
+
+// 解析后的聚合函数
+type aggregateSelect struct {
+	Name   string // 规范化后的名称，作为返回字段，如percentile(95)
+	Expr   string // SQL表达式
+	Single bool   // 只能在单表上计算
+}
+
+// 解析聚合函数，如avg、percentile(95)
+func parseAggregateFunc(s string) (aggregateSelect, error) {
+	m := aggregateFuncPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return aggregateSelect{}, fmt.Errorf("invalid aggregate function %q", s)
+	}
+	name, param := m[1], m[2]
+	fn, ok := aggregateFuncs[name]
+	if !ok {
+		return aggregateSelect{}, fmt.Errorf("unsupported aggregate function %q", name)
+	}
+	if !fn.param {
+		if param != "" {
+			return aggregateSelect{}, fmt.Errorf("aggregate function %s takes no parameter", name)
+		}
+		return aggregateSelect{Name: name, Expr: fn.expr, Single: fn.single}, nil
+	}
+
+	if param == "" {
+		return aggregateSelect{}, fmt.Errorf("aggregate function %s requires a parameter", name)
+	}
+	p, err := strconv.ParseFloat(param, 64)
+	if err != nil || p < fn.paramMin || p > fn.paramMax {
+		return aggregateSelect{}, fmt.Errorf("aggregate function %s parameter must be between %v and %v", name, fn.paramMin, fn.paramMax)
+	}
+	param = strconv.FormatFloat(p, 'f', -1, 64)
+	return aggregateSelect{Name: name + "(" + param + ")", Expr: fmt.Sprintf(fn.expr, param), Single: fn.single}, nil
+}
+
+// 跨设备聚合时拒绝只能在单表上计算的函数
+func checkMultiTable(selects []aggregateSelect) error {
+	for _, sel := range selects {
+		if !sel.Single {
+			continue
+		}
+		name := sel.Name
+		if i := strings.Index(name, "("); i >= 0 {
+			name = name[:i]
+		}
+		if instead := aggregateFuncs[name].instead; instead != "" {
+			return fmt.Errorf("aggregate function %s is not supported across devices, use %s instead", name, instead)
+		}
+		return fmt.Errorf("aggregate function %s is not supported across devices", name)
+	}
+	return nil
+}
+
+// 解析多个聚合函数，重复的函数只保留一个
+func parseAggregateFuncs(funcs []string) ([]aggregateSelect, error) {
+	if len(funcs) == 0 {
+		return nil, fmt.Errorf("aggregate function is required")
+	}
+	if len(funcs) > maxAggregateFuncs {
+		return nil, fmt.Errorf("at most %d aggregate functions are allowed", maxAggregateFuncs)
+	}
+	var selects []aggregateSelect
+	seen := make(map[string]bool)
+	for _, f := range funcs {
+		sel, err := parseAggregateFunc(f)
+		if err != nil {
+			return nil, err
+		}
+		if seen[sel.Name] {
+			continue
+		}
+		seen[sel.Name] = true
+		selects = append(selects, sel)
+	}
+	return selects, nil
+}
+
+// 空窗口填充方式
+var fillModes = map[string]string{
+	"none":   "NONE",
+	"null":   "NULL",
+	"value":  "VALUE",
+	"prev":   "PREV",
+	"next":   "NEXT",
+	"linear": "LINEAR",
+}
+
+// 生成FILL子句，fill为空时不填充；VALUE需要为每个聚合列指定填充值
+func fillClause(fill string, value float64, columns int) (string, error) {
+	if fill == "" {
+		return "", nil
+	}
+	mode, ok := fillModes[strings.ToLower(fill)]
+	if !ok {
+		return "", fmt.Errorf("unsupported fill mode %q", fill)
+	}
+	if mode != "VALUE" {
+		return fmt.Sprintf("FILL(%s)", mode), nil
+	}
+	values := make([]string, columns)
+	for i := range values {
+		values[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return fmt.Sprintf("FILL(VALUE, %s)", strings.Join(values, ",")), nil
+}
+
+// 生成select列，列名为v0、v1...
+func aggregateColumns(selects []aggregateSelect) string {
+	cols := make([]string, len(selects))
+	for i, sel := range selects {
+		cols[i] = fmt.Sprintf("%s AS v%d", sel.Expr, i)
+	}
+	return strings.Join(cols, ",")
+}
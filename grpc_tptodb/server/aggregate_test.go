@@ -0,0 +1,136 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseAggregateFunc(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantName string
+		wantExpr string
+		wantErr  bool
+	}{
+		{in: "avg", wantName: "avg", wantExpr: "AVG(number_v)"},
+		{in: " MAX ", wantName: "max", wantExpr: "MAX(number_v)"},
+		{in: "percentile(95)", wantName: "percentile(95)", wantExpr: "PERCENTILE(number_v, 95)"},
+		{in: "apercentile( 99.50 )", wantName: "apercentile(99.5)", wantExpr: "APERCENTILE(number_v, 99.5)"},
+		{in: "percentile(0)", wantName: "percentile(0)", wantExpr: "PERCENTILE(number_v, 0)"},
+		{in: "percentile(100)", wantName: "percentile(100)", wantExpr: "PERCENTILE(number_v, 100)"},
+		{in: "percentile(101)", wantErr: true},
+		{in: "percentile", wantErr: true},
+		{in: "avg(5)", wantErr: true},
+		{in: "median", wantErr: true},
+		{in: "avg(number_v); drop table x", wantErr: true},
+		{in: "percentile(-1)", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			sel, err := parseAggregateFunc(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseAggregateFunc(%q) = %+v, want error", tt.in, sel)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sel.Name != tt.wantName || sel.Expr != tt.wantExpr {
+				t.Fatalf("got %q %q, want %q %q", sel.Name, sel.Expr, tt.wantName, tt.wantExpr)
+			}
+		})
+	}
+}
+
+func TestParseAggregateFuncs(t *testing.T) {
+	tests := []struct {
+		name    string
+		funcs   []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", funcs: nil, wantErr: true},
+		{name: "duplicates removed", funcs: []string{"avg", "AVG", "percentile(95)", "percentile(95.0)"}, want: []string{"avg", "percentile(95)"}},
+		{name: "too many", funcs: strings.Split(strings.Repeat("avg,", maxAggregateFuncs+1), ",")[:maxAggregateFuncs+1], wantErr: true},
+		{name: "one invalid", funcs: []string{"avg", "bogus"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selects, err := parseAggregateFuncs(tt.funcs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			var names []string
+			for _, sel := range selects {
+				names = append(names, sel.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("names = %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckMultiTable(t *testing.T) {
+	tests := []struct {
+		funcs   []string
+		wantErr string
+	}{
+		{funcs: []string{"avg", "apercentile(95)", "twa"}},
+		{funcs: []string{"avg", "percentile(95)"}, wantErr: "use apercentile"},
+		{funcs: []string{"elapsed"}, wantErr: "elapsed is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.funcs, ","), func(t *testing.T) {
+			selects, err := parseAggregateFuncs(tt.funcs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkMultiTable(selects)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFillClause(t *testing.T) {
+	tests := []struct {
+		fill    string
+		value   float64
+		columns int
+		want    string
+		wantErr bool
+	}{
+		{fill: "", want: ""},
+		{fill: "none", want: "FILL(NONE)"},
+		{fill: "NULL", want: "FILL(NULL)"},
+		{fill: "prev", want: "FILL(PREV)"},
+		{fill: "next", want: "FILL(NEXT)"},
+		{fill: "linear", want: "FILL(LINEAR)"},
+		{fill: "value", value: 0, columns: 1, want: "FILL(VALUE, 0)"},
+		{fill: "value", value: -1.5, columns: 3, want: "FILL(VALUE, -1.5,-1.5,-1.5)"},
+		{fill: "nearest", wantErr: true},
+		{fill: "value); drop", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fill, func(t *testing.T) {
+			got, err := fillClause(tt.fill, tt.value, tt.columns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("fillClause = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
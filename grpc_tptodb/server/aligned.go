@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	db "thingspanel-TDengine/db"
+)
+
+// 对齐表单元格的填充方式
+var alignFillModes = map[string]bool{
+	"null":   true,
+	"prev":   true,
+	"next":   true,
+	"linear": true,
+	"value":  true,
+}
+
+// 一列中的一个数据点
+type alignedPoint struct {
+	ts    time.Time
+	value interface{}
+}
+
+// 按值类型取一行的值：bool返回true/false，数值返回float64，字符串返回string，均为占位值时返回false
+func typedValue(mp map[string]interface{}) (interface{}, bool) {
+	if b, ok := numberValue(mp["bool_v"]); ok && int(b) != db.BoolDefault {
+		return b != 0, true
+	}
+	if b, ok := mp["bool_v"].(bool); ok {
+		return b, true
+	}
+	if s, ok := mp["string_v"].(string); ok && s != db.StringDefault {
+		return s, true
+	}
+	if n, ok := numberValue(mp["number_v"]); ok && n != db.NumberDefault {
+		return n, true
+	}
+	return nil, false
+}
+
+// 把多列按时间对齐成宽表：所有点按时间排序，与行首时间相差不超过tolerance且该列尚无值的点并入当前行
+// 返回每行的时间和cells[列][行]，present标记单元格是否有原始数据
+func alignColumns(columns [][]alignedPoint, tolerance time.Duration) ([]time.Time, [][]interface{}, [][]bool) {
+	type point struct {
+		alignedPoint
+		col int
+	}
+	var points []point
+	for c, ps := range columns {
+		for _, p := range ps {
+			points = append(points, point{alignedPoint: p, col: c})
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool { return points[i].ts.Before(points[j].ts) })
+
+	var times []time.Time
+	cells := make([][]interface{}, len(columns))
+	present := make([][]bool, len(columns))
+	row := -1
+	for _, p := range points {
+		if row < 0 || p.ts.Sub(times[row]) > tolerance || present[p.col][row] {
+			times = append(times, p.ts)
+			for c := range columns {
+				cells[c] = append(cells[c], nil)
+				present[c] = append(present[c], false)
+			}
+			row++
+		}
+		cells[p.col][row] = p.value
+		present[p.col][row] = true
+	}
+	return times, cells, present
+}
+
+// 填充未对齐的单元格；linear和value只填充数值列
+func fillAligned(times []time.Time, cells [][]interface{}, present [][]bool, fill string, fillValue float64) error {
+	mode := strings.ToLower(fill)
+	if mode == "" || mode == "none" {
+		mode = "null"
+	}
+	if !alignFillModes[mode] {
+		return fmt.Errorf("unsupported fill mode %q, expected null, prev, next, linear or value", fill)
+	}
+	if mode == "null" {
+		return nil
+	}
+
+	for c := range cells {
+		// 有数据且全部为数值的列才按数值填充
+		numeric := false
+		for r := range cells[c] {
+			if !present[c][r] {
+				continue
+			}
+			if _, ok := cells[c][r].(float64); !ok {
+				numeric = false
+				break
+			}
+			numeric = true
+		}
+		for r := range cells[c] {
+			if present[c][r] {
+				continue
+			}
+			p, n := nearest(present[c], r, -1), nearest(present[c], r, 1)
+			switch mode {
+			case "prev":
+				if p >= 0 {
+					cells[c][r] = cells[c][p]
+				}
+			case "next":
+				if n >= 0 {
+					cells[c][r] = cells[c][n]
+				}
+			case "linear":
+				if numeric && p >= 0 && n >= 0 {
+					pv, nv := cells[c][p].(float64), cells[c][n].(float64)
+					ratio := float64(times[r].Sub(times[p])) / float64(times[n].Sub(times[p]))
+					cells[c][r] = pv + (nv-pv)*ratio
+				}
+			case "value":
+				if numeric {
+					cells[c][r] = fillValue
+				}
+			}
+		}
+	}
+	return nil
+}
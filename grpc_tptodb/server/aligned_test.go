@@ -0,0 +1,173 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	db "thingspanel-TDengine/db"
+)
+
+func TestAlignColumns(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+	p := func(ms int, v interface{}) alignedPoint { return alignedPoint{ts: at(ms), value: v} }
+
+	tests := []struct {
+		name        string
+		columns     [][]alignedPoint
+		tolerance   time.Duration
+		wantTimes   []time.Time
+		wantCells   [][]interface{}
+		wantPresent [][]bool
+	}{
+		{
+			name:        "same timestamps",
+			columns:     [][]alignedPoint{{p(0, 1.0), p(1000, 2.0)}, {p(0, "a"), p(1000, "b")}},
+			wantTimes:   []time.Time{at(0), at(1000)},
+			wantCells:   [][]interface{}{{1.0, 2.0}, {"a", "b"}},
+			wantPresent: [][]bool{{true, true}, {true, true}},
+		},
+		{
+			name:        "without tolerance nearby points get their own rows",
+			columns:     [][]alignedPoint{{p(0, 1.0)}, {p(5, 2.0)}},
+			wantTimes:   []time.Time{at(0), at(5)},
+			wantCells:   [][]interface{}{{1.0, nil}, {nil, 2.0}},
+			wantPresent: [][]bool{{true, false}, {false, true}},
+		},
+		{
+			// 行时间取行内最早的点
+			name:        "within tolerance",
+			columns:     [][]alignedPoint{{p(3, 1.0)}, {p(0, 2.0)}},
+			tolerance:   5 * time.Millisecond,
+			wantTimes:   []time.Time{at(0)},
+			wantCells:   [][]interface{}{{1.0}, {2.0}},
+			wantPresent: [][]bool{{true}, {true}},
+		},
+		{
+			// 容差从行首时间算起，不沿行内的点传递
+			name:        "tolerance measured from row start",
+			columns:     [][]alignedPoint{{p(0, 1.0)}, {p(4, 2.0)}, {p(8, 3.0)}},
+			tolerance:   5 * time.Millisecond,
+			wantTimes:   []time.Time{at(0), at(8)},
+			wantCells:   [][]interface{}{{1.0, nil}, {2.0, nil}, {nil, 3.0}},
+			wantPresent: [][]bool{{true, false}, {true, false}, {false, true}},
+		},
+		{
+			name:        "same column within tolerance starts a new row",
+			columns:     [][]alignedPoint{{p(0, 1.0), p(2, 2.0)}, {p(3, 3.0)}},
+			tolerance:   5 * time.Millisecond,
+			wantTimes:   []time.Time{at(0), at(2)},
+			wantCells:   [][]interface{}{{1.0, 2.0}, {nil, 3.0}},
+			wantPresent: [][]bool{{true, true}, {false, true}},
+		},
+		{
+			name:        "empty column",
+			columns:     [][]alignedPoint{{p(0, 1.0)}, nil},
+			wantTimes:   []time.Time{at(0)},
+			wantCells:   [][]interface{}{{1.0}, {nil}},
+			wantPresent: [][]bool{{true}, {false}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			times, cells, present := alignColumns(tt.columns, tt.tolerance)
+			if !reflect.DeepEqual(times, tt.wantTimes) {
+				t.Fatalf("times = %v, want %v", times, tt.wantTimes)
+			}
+			if !reflect.DeepEqual(cells, tt.wantCells) {
+				t.Fatalf("cells = %v, want %v", cells, tt.wantCells)
+			}
+			if !reflect.DeepEqual(present, tt.wantPresent) {
+				t.Fatalf("present = %v, want %v", present, tt.wantPresent)
+			}
+		})
+	}
+}
+
+func TestAlignColumnsNoData(t *testing.T) {
+	times, cells, present := alignColumns([][]alignedPoint{nil, nil}, time.Second)
+	if len(times) != 0 || len(cells) != 2 || len(cells[0]) != 0 || len(present[1]) != 0 {
+		t.Fatalf("got %v, %v, %v", times, cells, present)
+	}
+}
+
+func TestFillAligned(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	// 行间隔1、1、2秒，第0列为数值，第1列为字符串，第2列数值与字符串混合
+	times := []time.Time{base, base.Add(time.Second), base.Add(2 * time.Second), base.Add(4 * time.Second)}
+	table := func() ([][]interface{}, [][]bool) {
+		cells := [][]interface{}{
+			{nil, 10.0, nil, 30.0},
+			{"a", nil, "b", nil},
+			{1.0, nil, "x", nil},
+		}
+		present := [][]bool{
+			{false, true, false, true},
+			{true, false, true, false},
+			{true, false, true, false},
+		}
+		return cells, present
+	}
+	linear := 10.0 + (30.0-10.0)*(float64(time.Second)/float64(3*time.Second))
+
+	tests := []struct {
+		fill    string
+		want    [][]interface{}
+		wantErr bool
+	}{
+		{fill: "", want: [][]interface{}{{nil, 10.0, nil, 30.0}, {"a", nil, "b", nil}, {1.0, nil, "x", nil}}},
+		{fill: "none", want: [][]interface{}{{nil, 10.0, nil, 30.0}, {"a", nil, "b", nil}, {1.0, nil, "x", nil}}},
+		{fill: "null", want: [][]interface{}{{nil, 10.0, nil, 30.0}, {"a", nil, "b", nil}, {1.0, nil, "x", nil}}},
+		{fill: "prev", want: [][]interface{}{{nil, 10.0, 10.0, 30.0}, {"a", "a", "b", "b"}, {1.0, 1.0, "x", "x"}}},
+		{fill: "PREV", want: [][]interface{}{{nil, 10.0, 10.0, 30.0}, {"a", "a", "b", "b"}, {1.0, 1.0, "x", "x"}}},
+		{fill: "next", want: [][]interface{}{{10.0, 10.0, 30.0, 30.0}, {"a", "b", "b", nil}, {1.0, "x", "x", nil}}},
+		// 按时间插值：第2行在第1、3行之间的1/3处；字符串和混合列不插值
+		{fill: "linear", want: [][]interface{}{{nil, 10.0, linear, 30.0}, {"a", nil, "b", nil}, {1.0, nil, "x", nil}}},
+		{fill: "value", want: [][]interface{}{{-1.0, 10.0, -1.0, 30.0}, {"a", nil, "b", nil}, {1.0, nil, "x", nil}}},
+		{fill: "nearest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fill, func(t *testing.T) {
+			cells, present := table()
+			err := fillAligned(times, cells, present, tt.fill, -1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(cells, tt.want) {
+				t.Fatalf("cells = %v, want %v", cells, tt.want)
+			}
+			// 填充不改变原始数据标记
+			if _, want := table(); !reflect.DeepEqual(present, want) {
+				t.Fatalf("present changed to %v", present)
+			}
+		})
+	}
+}
+
+func TestTypedValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		row    map[string]interface{}
+		want   interface{}
+		wantOk bool
+	}{
+		{name: "bool", row: map[string]interface{}{"bool_v": int8(1), "number_v": db.NumberDefault, "string_v": db.StringDefault}, want: true, wantOk: true},
+		{name: "native bool", row: map[string]interface{}{"bool_v": false}, want: false, wantOk: true},
+		{name: "string", row: map[string]interface{}{"bool_v": int8(db.BoolDefault), "number_v": db.NumberDefault, "string_v": "on"}, want: "on", wantOk: true},
+		{name: "number", row: map[string]interface{}{"bool_v": int8(db.BoolDefault), "number_v": 21.5, "string_v": db.StringDefault}, want: 21.5, wantOk: true},
+		{name: "all placeholders", row: map[string]interface{}{"bool_v": int8(db.BoolDefault), "number_v": db.NumberDefault, "string_v": db.StringDefault}},
+		{name: "missing columns", row: map[string]interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := typedValue(tt.row)
+			if got != tt.want || ok != tt.wantOk {
+				t.Fatalf("got %v, %v; want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
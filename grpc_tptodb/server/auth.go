@@ -0,0 +1,214 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	db "thingspanel-TDengine/db"
+	"thingspanel-TDengine/logger"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 可访问全部租户数据的租户标识
+const allTenants = "*"
+
+type tenantKey struct{}
+
+// 从请求上下文获取调用方租户
+func tenantFromContext(ctx context.Context) (string, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	return tenant, ok
+}
+
+// 不需要认证的方法(健康检查、服务反射)
+var authExemptMethods = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+func authExempt(fullMethod string) bool {
+	for _, prefix := range authExemptMethods {
+		if strings.HasPrefix(fullMethod, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 一元调用认证拦截器
+func authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !viper.GetBool("grpc.auth.enable") || authExempt(info.FullMethod) {
+		return handler(ctx, req)
+	}
+
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeDevices(ctx, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// 流式调用认证拦截器
+func authStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !viper.GetBool("grpc.auth.enable") || authExempt(info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	ctx, err := authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// 在收到请求消息时校验设备权限
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *authServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return authorizeDevices(s.ctx, m)
+}
+
+// 校验metadata中的API Key(x-api-key)或JWT(authorization: Bearer)，并将租户写入上下文
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if keys := md.Get("x-api-key"); len(keys) > 0 && keys[0] != "" {
+		tenant, ok := apiKeyTenant(keys[0])
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid api key")
+		}
+		return context.WithValue(ctx, tenantKey{}, tenant), nil
+	}
+
+	if auths := md.Get("authorization"); len(auths) > 0 {
+		token, found := strings.CutPrefix(auths[0], "Bearer ")
+		if !found {
+			return nil, status.Error(codes.Unauthenticated, "invalid authorization header")
+		}
+		tenant, err := jwtTenant(token)
+		if err != nil {
+			logger.Grpc.Warn("jwt verify failed", "error", err)
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+		return context.WithValue(ctx, tenantKey{}, tenant), nil
+	}
+
+	return nil, status.Error(codes.Unauthenticated, "missing credentials")
+}
+
+type apiKey struct {
+	Key      string `mapstructure:"key"`
+	TenantId string `mapstructure:"tenant_id"`
+}
+
+// 启动时从grpc.auth.api_keys加载的API Key
+var apiKeys []apiKey
+
+// 加载API Key配置，空key忽略
+func loadAPIKeys() error {
+	var keys []apiKey
+	if err := viper.UnmarshalKey("grpc.auth.api_keys", &keys); err != nil {
+		return err
+	}
+	var loaded []apiKey
+	for _, k := range keys {
+		if k.Key != "" {
+			loaded = append(loaded, k)
+		}
+	}
+	apiKeys = loaded
+	return nil
+}
+
+// 配置的API Key对应的租户，逐个做常量时间比较
+func apiKeyTenant(key string) (string, bool) {
+	var tenant string
+	var found bool
+	for _, k := range apiKeys {
+		if subtle.ConstantTimeCompare([]byte(k.Key), []byte(key)) == 1 && !found {
+			tenant, found = k.TenantId, true
+		}
+	}
+	return tenant, found
+}
+
+// 校验HS256签名的JWT，返回租户claim
+func jwtTenant(tokenStr string) (string, error) {
+	secret := viper.GetString("grpc.auth.jwt_secret")
+	if secret == "" {
+		return "", fmt.Errorf("jwt is not configured")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+	if err != nil {
+		return "", err
+	}
+
+	claim := viper.GetString("grpc.auth.jwt_tenant_claim")
+	if claim == "" {
+		claim = "tenant_id"
+	}
+	tenant, ok := claims[claim].(string)
+	if !ok || tenant == "" {
+		return "", fmt.Errorf("claim %s not found", claim)
+	}
+	return tenant, nil
+}
+
+// 校验请求中的设备是否属于调用方租户
+func authorizeDevices(ctx context.Context, req interface{}) error {
+	tenant, ok := tenantFromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing credentials")
+	}
+	if tenant == allTenants {
+		return nil
+	}
+
+	var deviceIds []string
+	switch r := req.(type) {
+	case interface{ GetDeviceId() string }:
+		deviceIds = []string{r.GetDeviceId()}
+	case interface{ GetDeviceId() []string }:
+		deviceIds = r.GetDeviceId()
+	}
+
+	if len(deviceIds) == 0 {
+		return nil
+	}
+	owners, err := db.DeviceTenants(ctx, deviceIds)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to resolve device tenant", "devices", len(deviceIds), "error", err)
+		return status.Error(codes.Internal, "failed to resolve device tenant")
+	}
+	for _, deviceId := range deviceIds {
+		if owners[deviceId] != tenant {
+			return status.Errorf(codes.PermissionDenied, "device %s does not belong to tenant", deviceId)
+		}
+	}
+	return nil
+}
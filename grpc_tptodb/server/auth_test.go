@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "thingspanel-TDengine/grpc_tptodb"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "test-secret"
+
+func signToken(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func setupAuth(t *testing.T) {
+	t.Helper()
+	viper.Set("grpc.auth.api_keys", []map[string]interface{}{
+		{"key": "key-a", "tenant_id": "tenant-a"},
+		{"key": "key-admin", "tenant_id": allTenants},
+		{"key": "", "tenant_id": "ignored"},
+	})
+	viper.Set("grpc.auth.jwt_secret", testSecret)
+	viper.Set("grpc.auth.jwt_tenant_claim", "")
+	if err := loadAPIKeys(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		viper.Set("grpc.auth.api_keys", nil)
+		viper.Set("grpc.auth.jwt_secret", "")
+		apiKeys = nil
+	})
+}
+
+func TestAuthenticate(t *testing.T) {
+	setupAuth(t)
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Hour).Unix()
+
+	tests := []struct {
+		name       string
+		md         metadata.MD
+		wantTenant string
+		wantCode   codes.Code
+	}{
+		{name: "api key", md: metadata.Pairs("x-api-key", "key-a"), wantTenant: "tenant-a"},
+		{name: "admin api key", md: metadata.Pairs("x-api-key", "key-admin"), wantTenant: allTenants},
+		{name: "unknown api key", md: metadata.Pairs("x-api-key", "key-b"), wantCode: codes.Unauthenticated},
+		{name: "api key prefix", md: metadata.Pairs("x-api-key", "key"), wantCode: codes.Unauthenticated},
+		{name: "missing credentials", md: metadata.MD{}, wantCode: codes.Unauthenticated},
+		{
+			name:       "valid jwt",
+			md:         metadata.Pairs("authorization", "Bearer "+signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{"tenant_id": "tenant-j", "exp": future})),
+			wantTenant: "tenant-j",
+		},
+		{
+			name:     "expired jwt",
+			md:       metadata.Pairs("authorization", "Bearer "+signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{"tenant_id": "tenant-j", "exp": past})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong secret",
+			md:       metadata.Pairs("authorization", "Bearer "+signToken(t, jwt.SigningMethodHS256, []byte("other"), jwt.MapClaims{"tenant_id": "tenant-j"})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "wrong algorithm",
+			md:       metadata.Pairs("authorization", "Bearer "+signToken(t, jwt.SigningMethodHS512, []byte(testSecret), jwt.MapClaims{"tenant_id": "tenant-j"})),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "missing tenant claim",
+			md:       metadata.Pairs("authorization", "Bearer "+signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{"sub": "x"})),
+			wantCode: codes.Unauthenticated,
+		},
+		{name: "not a bearer token", md: metadata.Pairs("authorization", "Basic abc"), wantCode: codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, err := authenticate(metadata.NewIncomingContext(context.Background(), tt.md))
+			if tt.wantCode != codes.OK {
+				if status.Code(err) != tt.wantCode {
+					t.Fatalf("err = %v, want %v", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tenant, _ := tenantFromContext(ctx); tenant != tt.wantTenant {
+				t.Fatalf("tenant = %q, want %q", tenant, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestJwtTenantClaim(t *testing.T) {
+	setupAuth(t)
+	viper.Set("grpc.auth.jwt_tenant_claim", "org")
+	token := signToken(t, jwt.SigningMethodHS256, []byte(testSecret), jwt.MapClaims{"org": "tenant-o", "tenant_id": "other"})
+	tenant, err := jwtTenant(token)
+	if err != nil || tenant != "tenant-o" {
+		t.Fatalf("jwtTenant = %q, %v; want tenant-o", tenant, err)
+	}
+
+	viper.Set("grpc.auth.jwt_secret", "")
+	if _, err := jwtTenant(token); err == nil {
+		t.Fatal("jwt accepted without a configured secret")
+	}
+}
+
+func TestAuthorizeDevices(t *testing.T) {
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		wantCode codes.Code
+	}{
+		{name: "no tenant", ctx: context.Background(), wantCode: codes.Unauthenticated},
+		{name: "all tenants", ctx: context.WithValue(context.Background(), tenantKey{}, allTenants), wantCode: codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeDevices(tt.ctx, &pb.GetDeviceAttributesCurrentsRequest{DeviceId: "d1"})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("err = %v, want %v", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestAuthExempt(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"/grpc.health.v1.Health/Check", true},
+		{"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo", true},
+		{"/ThingsPanel/GetDeviceAttributesCurrents", false},
+	}
+	for _, tt := range tests {
+		if got := authExempt(tt.method); got != tt.want {
+			t.Errorf("authExempt(%q) = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	db "thingspanel-TDengine/db"
+
+	"gitee.com/chunanyong/zorm"
+)
+
+// 聚合窗口
+type aggregateBucket struct {
+	Start  time.Time
+	End    time.Time
+	Values []interface{} // 与聚合函数一一对应
+}
+
+// 单次请求最多的日历窗口数
+const maxCalendarBuckets = 1000
+
+// 日历窗口：d天、w周(周一开始)、n自然月、y自然年，如1d、1n
+var calendarWindowPattern = regexp.MustCompile(`^([1-9][0-9]*)([dwny])$`)
+
+type calendarWindow struct {
+	n    int
+	unit byte
+}
+
+func parseCalendarWindow(s string) (calendarWindow, error) {
+	m := calendarWindowPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return calendarWindow{}, fmt.Errorf("invalid calendar window %q, expected e.g. 1d, 1w, 1n, 1y", s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return calendarWindow{}, fmt.Errorf("invalid calendar window %q", s)
+	}
+	return calendarWindow{n: n, unit: m[2][0]}, nil
+}
+
+// 时间所在日历单位的起点(当地时间零点)
+func (w calendarWindow) truncate(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	switch w.unit {
+	case 'w':
+		day := time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case 'n':
+		return time.Date(y, mo, 1, 0, 0, 0, 0, t.Location())
+	case 'y':
+		return time.Date(y, 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// 第k个窗口的起点，按当地日历日期计算，不受夏令时和月份天数影响
+func (w calendarWindow) add(base time.Time, k int) time.Time {
+	switch w.unit {
+	case 'w':
+		return base.AddDate(0, 0, 7*w.n*k)
+	case 'n':
+		return base.AddDate(0, w.n*k, 0)
+	case 'y':
+		return base.AddDate(w.n*k, 0, 0)
+	default:
+		return base.AddDate(0, 0, w.n*k)
+	}
+}
+
+// 计算覆盖[start, end]的窗口边界
+func (w calendarWindow) boundaries(start, end time.Time, loc *time.Location) ([]time.Time, error) {
+	base := w.truncate(start.In(loc))
+	bounds := []time.Time{base}
+	for k := 1; !bounds[len(bounds)-1].After(end); k++ {
+		if len(bounds) > maxCalendarBuckets {
+			return nil, fmt.Errorf("too many calendar windows, at most %d are allowed", maxCalendarBuckets)
+		}
+		bounds = append(bounds, w.add(base, k))
+	}
+	return bounds, nil
+}
+
+// 按日历窗口聚合：Go中计算窗口边界，所有窗口用一条UNION ALL查询
+func queryCalendarBuckets(ctx context.Context, deviceId, key string, start, end time.Time, window calendarWindow, loc *time.Location, selects []aggregateSelect) ([]aggregateBucket, []bool, error) {
+	bounds, err := window.boundaries(start, end, loc)
+	if err != nil {
+		return nil, nil, err
+	}
+	buckets := make([]aggregateBucket, len(bounds)-1)
+	present := make([]bool, len(buckets))
+
+	// 查询范围为窗口与[start, end]的交集，数据库时间精度为微秒
+	endExclusive := end.Add(time.Microsecond)
+	cols := aggregateColumns(selects)
+	finder := zorm.NewFinder()
+	for i := range buckets {
+		buckets[i] = aggregateBucket{Start: bounds[i], End: bounds[i+1]}
+		lo, hi := bounds[i], bounds[i+1]
+		if lo.Before(start) {
+			lo = start
+		}
+		if hi.After(endExclusive) {
+			hi = endExclusive
+		}
+		if i > 0 {
+			finder.Append("UNION ALL")
+		}
+		finder.Append(fmt.Sprintf("SELECT %d AS idx,%s FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts < ? PARTITION BY tbname",
+			i, cols, db.DBName, db.SuperTableTv), deviceId, key, lo, hi)
+	}
+
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, v := range dataMap {
+		idx, ok := numberValue(v["idx"])
+		if !ok || int(idx) < 0 || int(idx) >= len(buckets) {
+			continue
+		}
+		i := int(idx)
+		buckets[i].Values = make([]interface{}, len(selects))
+		for j := range selects {
+			buckets[i].Values[j] = v[fmt.Sprintf("v%d", j)]
+		}
+		present[i] = true
+	}
+	return buckets, present, nil
+}
+
+// 在Go中填充空窗口，规则与TDengine FILL一致；fill为空或none时去掉空窗口
+func fillBuckets(buckets []aggregateBucket, present []bool, fill string, fillValue float64, columns int) ([]aggregateBucket, error) {
+	mode := "none"
+	if fill != "" {
+		var ok bool
+		if mode, ok = fillModes[strings.ToLower(fill)]; !ok {
+			return nil, fmt.Errorf("unsupported fill mode %q", fill)
+		}
+		mode = strings.ToLower(mode)
+	}
+
+	ret := make([]aggregateBucket, 0, len(buckets))
+	for i, b := range buckets {
+		if present[i] {
+			ret = append(ret, b)
+			continue
+		}
+		values := make([]interface{}, columns)
+		switch mode {
+		case "none":
+			continue
+		case "value":
+			for j := range values {
+				values[j] = fillValue
+			}
+		case "prev":
+			if p := nearest(present, i, -1); p >= 0 {
+				copy(values, buckets[p].Values)
+			}
+		case "next":
+			if n := nearest(present, i, 1); n >= 0 {
+				copy(values, buckets[n].Values)
+			}
+		case "linear":
+			p, n := nearest(present, i, -1), nearest(present, i, 1)
+			if p >= 0 && n >= 0 {
+				ratio := float64(b.Start.Sub(buckets[p].Start)) / float64(buckets[n].Start.Sub(buckets[p].Start))
+				for j := range values {
+					pv, ok1 := numberValue(buckets[p].Values[j])
+					nv, ok2 := numberValue(buckets[n].Values[j])
+					if ok1 && ok2 {
+						values[j] = pv + (nv-pv)*ratio
+					}
+				}
+			}
+		}
+		b.Values = values
+		ret = append(ret, b)
+	}
+	return ret, nil
+}
+
+// 向前(step=-1)或向后(step=1)查找最近的非空窗口
+func nearest(present []bool, i, step int) int {
+	for j := i + step; j >= 0 && j < len(present); j += step {
+		if present[j] {
+			return j
+		}
+	}
+	return -1
+}
+
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
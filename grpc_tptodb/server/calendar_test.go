@@ -0,0 +1,221 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestParseCalendarWindow(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    calendarWindow
+		wantErr bool
+	}{
+		{in: "1d", want: calendarWindow{n: 1, unit: 'd'}},
+		{in: " 2W ", want: calendarWindow{n: 2, unit: 'w'}},
+		{in: "3n", want: calendarWindow{n: 3, unit: 'n'}},
+		{in: "1y", want: calendarWindow{n: 1, unit: 'y'}},
+		{in: "0d", wantErr: true},
+		{in: "1h", wantErr: true},
+		{in: "d", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseCalendarWindow(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarBoundaries(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	shanghai := loadLocation(t, "Asia/Shanghai")
+	tests := []struct {
+		name   string
+		window string
+		loc    *time.Location
+		start  time.Time
+		end    time.Time
+		want   []time.Time
+	}{
+		{
+			// 2024-03-10美国开始夏令时，当天只有23小时
+			name:   "dst spring forward",
+			window: "1d",
+			loc:    newYork,
+			start:  time.Date(2024, 3, 9, 12, 0, 0, 0, newYork),
+			end:    time.Date(2024, 3, 10, 12, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 3, 9, 0, 0, 0, 0, newYork),
+				time.Date(2024, 3, 10, 0, 0, 0, 0, newYork),
+				time.Date(2024, 3, 11, 0, 0, 0, 0, newYork),
+			},
+		},
+		{
+			// 2024-11-03美国结束夏令时，当天有25小时
+			name:   "dst fall back",
+			window: "1d",
+			loc:    newYork,
+			start:  time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+			end:    time.Date(2024, 11, 3, 23, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 11, 3, 0, 0, 0, 0, newYork),
+				time.Date(2024, 11, 4, 0, 0, 0, 0, newYork),
+			},
+		},
+		{
+			name:   "month end",
+			window: "1n",
+			loc:    shanghai,
+			start:  time.Date(2024, 1, 31, 10, 0, 0, 0, shanghai),
+			end:    time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 2, 1, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 4, 1, 0, 0, 0, 0, shanghai),
+			},
+		},
+		{
+			// 输入为UTC，按Asia/Shanghai的日期切分
+			name:   "utc input in local days",
+			window: "1d",
+			loc:    shanghai,
+			start:  time.Date(2024, 2, 28, 20, 0, 0, 0, time.UTC),
+			end:    time.Date(2024, 2, 29, 1, 0, 0, 0, time.UTC),
+			want: []time.Time{
+				time.Date(2024, 2, 29, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 3, 1, 0, 0, 0, 0, shanghai),
+			},
+		},
+		{
+			// 2024-03-06是周三，周窗口从周一开始
+			name:   "week starts monday",
+			window: "1w",
+			loc:    shanghai,
+			start:  time.Date(2024, 3, 6, 8, 0, 0, 0, shanghai),
+			end:    time.Date(2024, 3, 11, 0, 0, 0, 0, shanghai),
+			want: []time.Time{
+				time.Date(2024, 3, 4, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 3, 11, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 3, 18, 0, 0, 0, 0, shanghai),
+			},
+		},
+		{
+			name:   "quarter",
+			window: "3n",
+			loc:    shanghai,
+			start:  time.Date(2023, 11, 30, 0, 0, 0, 0, shanghai),
+			end:    time.Date(2024, 2, 29, 0, 0, 0, 0, shanghai),
+			want: []time.Time{
+				time.Date(2023, 11, 1, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 2, 1, 0, 0, 0, 0, shanghai),
+				time.Date(2024, 5, 1, 0, 0, 0, 0, shanghai),
+			},
+		},
+		{
+			name:   "leap year",
+			window: "1y",
+			loc:    shanghai,
+			start:  time.Date(2024, 2, 29, 0, 0, 0, 0, shanghai),
+			end:    time.Date(2024, 12, 31, 0, 0, 0, 0, shanghai),
+			want: []time.Time{
+				time.Date(2024, 1, 1, 0, 0, 0, 0, shanghai),
+				time.Date(2025, 1, 1, 0, 0, 0, 0, shanghai),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := parseCalendarWindow(tt.window)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := w.boundaries(tt.start, tt.end, tt.loc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Fatalf("bound %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarBoundariesTooMany(t *testing.T) {
+	w := calendarWindow{n: 1, unit: 'd'}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := w.boundaries(start, start.AddDate(0, 0, maxCalendarBuckets+1), time.UTC); err == nil {
+		t.Fatal("expected too many windows error")
+	}
+	if _, err := w.boundaries(start, start.AddDate(0, 0, maxCalendarBuckets-1), time.UTC); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFillBuckets(t *testing.T) {
+	newYork := loadLocation(t, "America/New_York")
+	// 日窗口跨越夏令时，第2、4个窗口为空；线性插值按窗口起点的实际时间差计算
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, newYork) }
+	buckets := func() []aggregateBucket {
+		return []aggregateBucket{
+			{Start: day(9), End: day(10), Values: []interface{}{10.0}},
+			{Start: day(10), End: day(11)},
+			{Start: day(11), End: day(12), Values: []interface{}{33.0}},
+			{Start: day(12), End: day(13)},
+		}
+	}
+	present := []bool{true, false, true, false}
+	// day(9)->day(10)为24小时，day(10)->day(11)为23小时
+	linear := 10.0 + (33.0-10.0)*(float64(24*time.Hour)/float64(47*time.Hour))
+
+	tests := []struct {
+		fill    string
+		want    []interface{} // 每个返回窗口的第一个值
+		wantErr bool
+	}{
+		{fill: "", want: []interface{}{10.0, 33.0}},
+		{fill: "none", want: []interface{}{10.0, 33.0}},
+		{fill: "null", want: []interface{}{10.0, nil, 33.0, nil}},
+		{fill: "value", want: []interface{}{10.0, -1.0, 33.0, -1.0}},
+		{fill: "prev", want: []interface{}{10.0, 10.0, 33.0, 33.0}},
+		{fill: "next", want: []interface{}{10.0, 33.0, 33.0, nil}},
+		{fill: "linear", want: []interface{}{10.0, linear, 33.0, nil}},
+		{fill: "nearest", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fill, func(t *testing.T) {
+			got, err := fillBuckets(buckets(), present, tt.fill, -1, 1)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d buckets, want %d", len(got), len(tt.want))
+			}
+			for i, b := range got {
+				if b.Values[0] != tt.want[i] {
+					t.Fatalf("bucket %d = %v, want %v", i, b.Values[0], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 单次查询最多的设备数
+const maxActivityDevices = 1000
+
+// 请求中的时长(毫秒)，为0时使用配置(秒)，配置为空时使用def
+func activityDuration(ms int64, key string, def time.Duration) (time.Duration, error) {
+	if ms < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "%s must not be negative", key)
+	}
+	if ms > 0 {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	if d := viper.GetDuration("activity."+key) * time.Second; d > 0 {
+		return d, nil
+	}
+	return def, nil
+}
+
+// 设备活跃度和在线状态：最后上报时间取自最新值缓存，速率和离线区间查询ts_kv
+func (s *server) GetDeviceActivity(ctx context.Context, in *pb.GetDeviceActivityRequest) (*pb.GetDeviceActivityReply, error) {
+	deviceIds := make([]string, 0, len(in.GetDeviceId()))
+	seen := make(map[string]bool)
+	for _, id := range in.GetDeviceId() {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			deviceIds = append(deviceIds, id)
+		}
+	}
+	if len(deviceIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "device_id is required")
+	}
+	if len(deviceIds) > maxActivityDevices {
+		return nil, status.Errorf(codes.InvalidArgument, "too many devices, at most %d are allowed", maxActivityDevices)
+	}
+	threshold, err := activityDuration(in.GetOfflineThreshold(), "offline_threshold", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	rateWindow, err := activityDuration(in.GetRateWindow(), "rate_window", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	withGaps := in.GetStartTime() != 0 && in.GetEndTime() != 0
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	if withGaps {
+		if err := checkTimeRange(startTime, endTime); err != nil {
+			return nil, err
+		}
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	latest, err := db.GetLatest(ctx, deviceIds, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to get latest values", "devices", len(deviceIds), "error", err)
+		return nil, err
+	}
+	points, err := queryDevicePoints(ctx, deviceIds, now.Add(-rateWindow), now)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query device points", "devices", len(deviceIds), "error", err)
+		return nil, err
+	}
+	var gaps map[string][]stateInterval
+	if withGaps {
+		if gaps, err = queryOfflineGaps(ctx, deviceIds, startTime, endTime, now, threshold); err != nil {
+			logger.FromContext(ctx).Error("failed to query offline gaps", "devices", len(deviceIds), "error", err)
+			return nil, err
+		}
+	}
+
+	data := make(map[string]interface{}, len(deviceIds))
+	for _, deviceId := range deviceIds {
+		var lastSeen time.Time
+		for _, v := range latest[deviceId] {
+			if v.Ts.After(lastSeen) {
+				lastSeen = v.Ts
+			}
+		}
+		m := map[string]interface{}{
+			"status":   "unknown",
+			"points":   points[deviceId].points,
+			"messages": points[deviceId].messages,
+			"rate":     float64(points[deviceId].messages) / rateWindow.Minutes(),
+		}
+		if !lastSeen.IsZero() {
+			m["last_seen"] = tf.Format(lastSeen)
+			m["status"] = "offline"
+			if now.Sub(lastSeen) <= threshold {
+				m["status"] = "online"
+			}
+		}
+		if withGaps {
+			offline := make([]map[string]interface{}, 0, len(gaps[deviceId]))
+			for _, g := range gaps[deviceId] {
+				offline = append(offline, map[string]interface{}{
+					"start":    tf.Format(g.Start),
+					"end":      tf.Format(g.End),
+					"duration": g.End.Sub(g.Start).Milliseconds(),
+				})
+			}
+			m["offline"] = offline
+		}
+		data[deviceId] = m
+	}
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal device activity", "error", err)
+		return &pb.GetDeviceActivityReply{Status: 1, Message: err.Error(), Data: string("{}")}, nil
+	}
+	logger.FromContext(ctx).Debug("GetDeviceActivity", "devices", len(deviceIds))
+	return &pb.GetDeviceActivityReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+}
+
+// 设备在时间范围内的数据点数(每个key一行)和消息数(同一条消息的各key时间相同，按不同时间计数)
+type devicePoints struct {
+	points, messages int64
+}
+
+// 各设备在时间范围内的数据点数和消息数
+func queryDevicePoints(ctx context.Context, deviceIds []string, start, end time.Time) (map[string]devicePoints, error) {
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT device_id,SUM(cnt) AS points,COUNT(*) AS messages FROM "+
+		"(SELECT device_id,ts,COUNT(*) AS cnt FROM %s.%s WHERE device_id in (?) AND ts >= ? AND ts <= ? GROUP BY device_id,ts) GROUP BY device_id",
+		db.DBName, db.SuperTableTv), deviceIds, start, end)
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[string]devicePoints, len(dataMap))
+	for _, v := range dataMap {
+		points, _ := numberValue(v["points"])
+		messages, _ := numberValue(v["messages"])
+		ret[fmt.Sprintf("%v", v["device_id"])] = devicePoints{points: int64(points), messages: int64(messages)}
+	}
+	return ret, nil
+}
+
+// 用SESSION窗口划分连续上报的时段，时段之间即为超过threshold未上报的离线区间
+// 查询开始到第一次上报、最后一次上报到查询结束(不晚于now)超过threshold时也算离线
+func queryOfflineGaps(ctx context.Context, deviceIds []string, start, end, now time.Time, threshold time.Duration) (map[string][]stateInterval, error) {
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT device_id,_wstart AS ws,_wend AS we FROM %s.%s WHERE device_id in (?) AND ts >= ? AND ts <= ? PARTITION BY device_id SESSION(ts, %da)",
+		db.DBName, db.SuperTableTv, threshold.Milliseconds()), deviceIds, start, end)
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	sessions := make(map[string][]stateInterval)
+	for _, v := range dataMap {
+		ws, err := timeutil.Parse(v["ws"])
+		if err != nil {
+			return nil, err
+		}
+		we, err := timeutil.Parse(v["we"])
+		if err != nil {
+			return nil, err
+		}
+		deviceId := fmt.Sprintf("%v", v["device_id"])
+		sessions[deviceId] = append(sessions[deviceId], stateInterval{Start: ws, End: we})
+	}
+
+	until := end
+	if until.After(now) {
+		until = now
+	}
+	ret := make(map[string][]stateInterval, len(deviceIds))
+	for _, deviceId := range deviceIds {
+		ret[deviceId] = offlineGaps(sessions[deviceId], start, until, threshold)
+	}
+	return ret, nil
+}
+
+// 由上报时段计算start到until之间超过threshold的离线区间
+func offlineGaps(sessions []stateInterval, start, until time.Time, threshold time.Duration) []stateInterval {
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].Start.Before(sessions[j].Start) })
+	gaps := make([]stateInterval, 0)
+	prev := start
+	for _, s := range sessions {
+		if s.Start.Sub(prev) > threshold {
+			gaps = append(gaps, stateInterval{Start: prev, End: s.Start})
+		}
+		if s.End.After(prev) {
+			prev = s.End
+		}
+	}
+	if until.Sub(prev) > threshold {
+		gaps = append(gaps, stateInterval{Start: prev, End: until})
+	}
+	return gaps
+}
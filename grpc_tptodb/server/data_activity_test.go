@@ -0,0 +1,98 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOfflineGaps(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	start, until := at(0), at(120)
+	threshold := 5 * time.Minute
+	session := func(from, to int) stateInterval { return stateInterval{Start: at(from), End: at(to)} }
+
+	tests := []struct {
+		name     string
+		sessions []stateInterval
+		until    time.Time
+		want     []stateInterval
+	}{
+		{
+			name: "never reported is offline for the whole range",
+			want: []stateInterval{session(0, 120)},
+		},
+		{
+			name:     "continuous reporting has no gaps",
+			sessions: []stateInterval{session(0, 118)},
+			want:     []stateInterval{},
+		},
+		{
+			name:     "gap between sessions",
+			sessions: []stateInterval{session(0, 30), session(60, 120)},
+			want:     []stateInterval{session(30, 60)},
+		},
+		{
+			name:     "gap at threshold is not offline",
+			sessions: []stateInterval{session(0, 30), session(35, 120)},
+			want:     []stateInterval{},
+		},
+		{
+			name:     "late first report and early last report",
+			sessions: []stateInterval{session(20, 50)},
+			want:     []stateInterval{session(0, 20), session(50, 120)},
+		},
+		{
+			name:     "unsorted sessions",
+			sessions: []stateInterval{session(60, 120), session(0, 30)},
+			want:     []stateInterval{session(30, 60)},
+		},
+		{
+			// 结束时间在未来时只算到until
+			name:     "trailing gap ends at until",
+			sessions: []stateInterval{session(0, 50)},
+			until:    at(90),
+			want:     []stateInterval{session(50, 90)},
+		},
+		{
+			name:     "trailing gap shorter than threshold",
+			sessions: []stateInterval{session(0, 50)},
+			until:    at(54),
+			want:     []stateInterval{},
+		},
+		{
+			name:     "single point sessions",
+			sessions: []stateInterval{session(10, 10), session(40, 40)},
+			until:    at(42),
+			want:     []stateInterval{session(0, 10), session(10, 40)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := until
+			if !tt.until.IsZero() {
+				u = tt.until
+			}
+			got := offlineGaps(tt.sessions, start, u, threshold)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActivityDuration(t *testing.T) {
+	if got, err := activityDuration(1500, "offline_threshold", time.Minute); err != nil || got != 1500*time.Millisecond {
+		t.Fatalf("got %v, %v", got, err)
+	}
+	if got, err := activityDuration(0, "offline_threshold", time.Minute); err != nil || got != time.Minute {
+		t.Fatalf("got %v, %v; want the default", got, err)
+	}
+	if _, err := activityDuration(-1, "offline_threshold", time.Minute); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("err = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 同一次查询key目录的最多设备数
+const maxCatalogDevices = 1000
+
+// 设备或模型的key目录，读取ts_kv_catalog，不扫描ts_kv
+func (s *server) GetKeyCatalog(ctx context.Context, in *pb.GetKeyCatalogRequest) (*pb.GetKeyCatalogReply, error) {
+	deviceIds := make([]string, 0, len(in.GetDeviceId()))
+	seen := make(map[string]bool)
+	for _, id := range in.GetDeviceId() {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			deviceIds = append(deviceIds, id)
+		}
+	}
+	if modelId := in.GetModelId(); modelId != "" {
+		var err error
+		if deviceIds, err = modelDevices(deviceIds, in.GetDeviceModel(), modelId); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if len(deviceIds) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "no device of model %s in device_model", modelId)
+		}
+	}
+	if len(deviceIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "device_id or model_id is required")
+	}
+	if len(deviceIds) > maxCatalogDevices {
+		return nil, status.Errorf(codes.InvalidArgument, "too many devices, at most %d are allowed", maxCatalogDevices)
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := db.GetCatalog(ctx, deviceIds)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query key catalog", "devices", len(deviceIds), "error", err)
+		return nil, err
+	}
+
+	// 按key合并多个设备，值类型取最后上报的
+	byKey := make(map[string]*db.CatalogEntry)
+	devices := make(map[string]int)
+	for i, e := range entries {
+		if !strings.HasPrefix(e.Key, in.GetKeyPrefix()) {
+			continue
+		}
+		devices[e.Key]++
+		cur, ok := byKey[e.Key]
+		if !ok {
+			byKey[e.Key] = &entries[i]
+			continue
+		}
+		cur.Count += e.Count
+		if e.FirstTs.Before(cur.FirstTs) {
+			cur.FirstTs = e.FirstTs
+		}
+		if e.LastTs.After(cur.LastTs) {
+			cur.LastTs = e.LastTs
+			cur.ValueType = e.ValueType
+		}
+	}
+
+	dataList := make([]map[string]interface{}, 0, len(byKey))
+	for k, e := range byKey {
+		m := map[string]interface{}{
+			"key":      k,
+			"type":     e.ValueType,
+			"first_ts": tf.Format(e.FirstTs),
+			"last_ts":  tf.Format(e.LastTs),
+			"count":    e.Count,
+		}
+		if len(deviceIds) > 1 {
+			m["devices"] = devices[k]
+		}
+		dataList = append(dataList, m)
+	}
+	sort.Slice(dataList, func(i, j int) bool { return dataList[i]["key"].(string) < dataList[j]["key"].(string) })
+
+	jsonStr, err := json.Marshal(dataList)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal key catalog", "error", err)
+		return &pb.GetKeyCatalogReply{Status: 1, Message: err.Error(), Data: string("[]")}, nil
+	}
+	logger.FromContext(ctx).Debug("GetKeyCatalog", "devices", len(deviceIds), "keys", len(dataList))
+	return &pb.GetKeyCatalogReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+}
@@ -4,180 +4,60 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-	"time"
+	"sort"
 
 	db "thingspanel-TDengine/db"
 	pb "thingspanel-TDengine/grpc_tptodb"
-
-	"gitee.com/chunanyong/zorm"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
 )
 
-const layout = "2006-01-02 15:04:05.999 -0700 MST"
-
 // 设备数据当前值查询
 func (s *server) GetDeviceAttributesCurrents(ctx context.Context, in *pb.GetDeviceAttributesCurrentsRequest) (*pb.GetDeviceAttributesCurrentsReply, error) {
 	var deviceId string = in.GetDeviceId()
 	var attributeList []string = in.GetAttribute()
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
 
-	finder := zorm.NewFinder()
 	var retMap = make([]map[string]interface{}, 0)
 	var dataMap = make([]map[string]interface{}, 0)
-	var err error
 
-	// 查询表ts_kv
-	if len(attributeList) == 0 { //返回当前设备的所有遥测key的最新值
-		finder.Append(fmt.Sprintf("SELECT distinct k FROM %s.%s WHERE device_id = ?",
-			db.DBName, db.SuperTableTv), deviceId)
-		dataMaptmp1, err := zorm.QueryMap(ctx, finder, nil)
+	// 从最新值缓存读取
+	if len(attributeList) == 1 && attributeList[0] == "" { //返回设备id的最新一条属性值
+		latest, err := db.GetLatest(ctx, []string{deviceId}, nil)
 		if err != nil {
+			logger.FromContext(ctx).Error("failed to get latest values", "error", err)
 			return nil, err
 		}
-
-		for _, mp := range dataMaptmp1 {
-			if _, ok := mp["k"]; ok {
-				attributeList = append(attributeList, fmt.Sprintf("%v", mp["k"]))
+		var last *db.LatestValue
+		for i, v := range latest[deviceId] {
+			if last == nil || v.Ts.After(last.Ts) {
+				last = &latest[deviceId][i]
 			}
 		}
-
-		for i := 0; i < len(attributeList); i++ {
-			finder = zorm.NewFinder()
-			finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND k in (?) order by ts desc limit 1",
-				db.DBName, db.SuperTableTv), deviceId, attributeList[i])
-			dataMaptmp2, err := zorm.QueryMap(ctx, finder, nil)
-			if err != nil {
-				log.Println("QueryMap: ", err)
-				continue
-			}
-			if len(dataMaptmp2) > 0 {
-				dataMap = append(dataMap, dataMaptmp2...)
-			}
-		}
-	} else if len(attributeList) == 1 && attributeList[0] == "" { //返回设备id的最新一条属性值
-		finder = zorm.NewFinder()
-		finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? order by ts desc limit 1",
-			db.DBName, db.SuperTableTv), deviceId)
-		dataMaptmp2, err := zorm.QueryMap(ctx, finder, nil)
-		if err != nil {
-			log.Println("QueryMap: ", err)
-			return nil, err
-		}
-		if len(dataMaptmp2) > 0 {
-			dataMap = append(dataMap, dataMaptmp2...)
-		}
-		for _, mp := range dataMap {
-			m := make(map[string]interface{}, 0)
-			if string_v, ok := mp["string_v"]; ok {
-				if fmt.Sprintf("%v", string_v) != db.StringDefault {
-					m["string_v"] = string_v
-				}
-			}
-
-			if number_v, ok := mp["number_v"]; ok {
-				if v, ok := number_v.(float64); ok && v != db.NumberDefault {
-					m["number_v"] = v
-				}
-			}
-
-			if bool_v, ok := mp["bool_v"]; ok {
-				if v, ok := bool_v.(int); ok && v != db.BoolDefault {
-					m["bool_v"] = v
-				}
-			}
-
-			if ts, ok := mp["ts"]; ok && ts != "" {
-				utcTimeStr := fmt.Sprintf("%s", ts)
-
-				// 解析UTC时间
-				utcTime, err := time.Parse(layout, utcTimeStr)
-				if err != nil {
-					return nil, err
-				}
-				m["ts"] = utcTime.UnixMilli()
-			}
-
-			if _, ok := mp["k"]; ok {
-				m["key"] = mp["k"]
-			}
-			m["device_id"] = deviceId
-			if _, ok := mp["tenant_id"]; ok {
-				m["tenant_id"] = mp["tenant_id"]
-			}
-			if len(m) > 0 {
-				retMap = append(retMap, m)
-			}
+		if last != nil {
+			dataMap = append(dataMap, last.Map())
 		}
-
-		// 将map转成json
-		dataJson, err := json.Marshal(retMap)
+	} else { //len(attributeList) == 0 时返回当前设备的所有遥测key的最新值
+		latest, err := db.GetLatest(ctx, []string{deviceId}, attributeList)
 		if err != nil {
+			logger.FromContext(ctx).Error("failed to get latest values", "error", err)
 			return nil, err
 		}
-
-		log.Println("dataJson: ", string(dataJson))
-		return &pb.GetDeviceAttributesCurrentsReply{Status: 1, Message: "", Data: string(dataJson)}, nil
-
-	} else {
-		for i := 0; i < len(attributeList); i++ {
-			finder = zorm.NewFinder()
-			finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND k in (?) order by ts desc limit 1",
-				db.DBName, db.SuperTableTv), deviceId, attributeList[i])
-			dataMaptmp2, err := zorm.QueryMap(ctx, finder, nil)
-			if err != nil {
-				log.Println("QueryMap: ", err)
-				continue
-			}
-			if len(dataMaptmp2) > 0 {
-				dataMap = append(dataMap, dataMaptmp2...)
-			}
+		for _, v := range latest[deviceId] {
+			dataMap = append(dataMap, v.Map())
 		}
 	}
 
 	for _, mp := range dataMap {
-		m := make(map[string]interface{}, 0)
-		if string_v, ok := mp["string_v"]; ok {
-			if fmt.Sprintf("%v", string_v) != db.StringDefault {
-				m["string_v"] = string_v
-			}
-		}
-
-		if number_v, ok := mp["number_v"]; ok {
-			if v, ok := number_v.(float64); ok && v != db.NumberDefault {
-				m["number_v"] = v
-			}
-		}
-
-		if bool_v, ok := mp["bool_v"]; ok {
-			if v, ok := bool_v.(int); ok && v != db.BoolDefault {
-				m["bool_v"] = v
-			}
-		}
-
-		if ts, ok := mp["ts"]; ok && ts != "" {
-
-			utcTimeStr := fmt.Sprintf("%s", ts)
-			loc, _ := time.LoadLocation("Asia/Shanghai") // 例如，中国上海的时区
-
-			// 解析UTC时间
-			utcTime, err := time.Parse(layout, utcTimeStr)
-			if err != nil {
-				return nil, err
-			}
-			// 将UTC时间转换为本地时间
-			localTime := utcTime.In(loc)
-			m["ts"] = localTime
-		}
-
-		if _, ok := mp["k"]; ok {
-			m["key"] = mp["k"]
+		m, err := currentRow(mp, tf)
+		if err != nil {
+			return nil, err
 		}
 		m["device_id"] = deviceId
-		if _, ok := mp["tenant_id"]; ok {
-			m["tenant_id"] = mp["tenant_id"]
-		}
-		if len(m) > 0 {
-			retMap = append(retMap, m)
-		}
+		retMap = append(retMap, m)
 	}
 
 	// 将map转成json
@@ -186,7 +66,7 @@ func (s *server) GetDeviceAttributesCurrents(ctx context.Context, in *pb.GetDevi
 		return nil, err
 	}
 
-	log.Println("dataJson: ", string(dataJson))
+	logger.FromContext(ctx).Debug("response", "data", string(dataJson))
 	return &pb.GetDeviceAttributesCurrentsReply{Status: 1, Message: "", Data: string(dataJson)}, nil
 }
 
@@ -194,101 +74,110 @@ func (s *server) GetDeviceAttributesCurrents(ctx context.Context, in *pb.GetDevi
 func (s *server) GetDeviceAttributesCurrentList(ctx context.Context, in *pb.GetDeviceAttributesCurrentListRequest) (*pb.GetDeviceAttributesCurrentListReply, error) {
 	var deviceId string = in.GetDeviceId()
 	var attributeList []string = in.GetAttribute()
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
 
-	finder := zorm.NewFinder()
 	var dataMap = make([]map[string]interface{}, 0)
-	var err error
 
-	// 查询表ts_kv
-	if len(attributeList) == 0 {
-		finder.Append(fmt.Sprintf("SELECT distinct k FROM %s.%s WHERE device_id = ?",
-			db.DBName, db.SuperTableTv), deviceId)
-		dataMap, err = zorm.QueryMap(ctx, finder, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, mp := range dataMap {
-			if _, ok := mp["k"]; ok {
-				attributeList = append(attributeList, fmt.Sprintf("%v", mp["k"]))
-			}
-		}
+	// 从最新值缓存读取，按时间倒序
+	latest, err := db.GetLatest(ctx, []string{deviceId}, attributeList)
+	if err != nil {
+		return nil, err
+	}
+	values := latest[deviceId]
+	sort.Slice(values, func(i, j int) bool { return values[i].Ts.After(values[j].Ts) })
+	for _, v := range values {
+		dataMap = append(dataMap, v.Map())
+	}
 
-		finder = zorm.NewFinder()
-		finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND k in (?) order by ts desc",
-			db.DBName, db.SuperTableTv), deviceId, attributeList)
-		dataMap, err = zorm.QueryMap(ctx, finder, nil)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND k in (?) order by ts desc",
-			db.DBName, db.SuperTableTv), deviceId, attributeList)
-		dataMap, err = zorm.QueryMap(ctx, finder, nil)
+	var dataMapList []map[string]interface{}
+	for _, mp := range dataMap {
+		m, err := currentRow(mp, tf)
 		if err != nil {
 			return nil, err
 		}
+		m["device_id"] = deviceId
+		dataMapList = append(dataMapList, m)
 	}
 
-	var dataMapList []map[string]interface{}
-	for _, mp := range dataMap {
-		m := make(map[string]interface{}, 0)
-
-		if string_v, ok := mp["string_v"]; ok {
-			// fmt.Printf("string_v:%+v\n", string_v)
-			if fmt.Sprintf("%v", string_v) != db.StringDefault {
-				m["string_v"] = string_v
-			}
-		}
+	// 将map转成json
+	dataJson, err := json.Marshal(dataMapList)
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).Debug("response", "data", string(dataJson))
+	return &pb.GetDeviceAttributesCurrentListReply{Status: 1, Message: "", Data: string(dataJson)}, nil
+}
 
-		if number_v, ok := mp["number_v"]; ok {
-			// fmt.Printf("number_v:%+v\n", number_v)
-			if v, ok := number_v.(float64); ok && v != db.NumberDefault {
-				m["number_v"] = v
-			}
-		}
+// 多设备当前数据批量查询
+func (s *server) GetMultiDeviceAttributesCurrents(ctx context.Context, in *pb.GetMultiDeviceAttributesCurrentsRequest) (*pb.GetMultiDeviceAttributesCurrentsReply, error) {
+	deviceIds := in.GetDeviceId()
+	if len(deviceIds) == 0 {
+		return &pb.GetMultiDeviceAttributesCurrentsReply{Status: 0, Message: "device_id is required", Data: ""}, nil
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
 
-		if bool_v, ok := mp["bool_v"]; ok {
-			// fmt.Printf("bool_v:%+v\n", bool_v)
-			if v, ok := bool_v.(int); ok && v != db.BoolDefault {
-				m["bool_v"] = v
-			}
-		}
+	dataMap, err := queryLatestValues(ctx, deviceIds, in.GetAttribute(), tf)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query latest values", "devices", len(deviceIds), "error", err)
+		return nil, err
+	}
 
-		if ts, ok := mp["ts"]; ok && ts != "" {
-			utcTimeStr := fmt.Sprintf("%s", ts)
-			loc, _ := time.LoadLocation("Asia/Shanghai") // 例如，中国上海的时区
+	dataJson, err := json.Marshal(dataMap)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetMultiDeviceAttributesCurrentsReply{Status: 1, Message: "", Data: string(dataJson)}, nil
+}
 
-			// 解析UTC时间
-			utcTime, err := time.Parse(layout, utcTimeStr)
-			if err != nil {
-				return nil, err
-			}
-			// 将UTC时间转换为本地时间
-			localTime := utcTime.In(loc)
-			m["ts"] = localTime
-		}
+// 查询多个设备各key的最新值(最新值缓存，未命中时一次LAST_ROW查询回源)，keys为空时返回全部key
+// 返回 device_id -> 当前值列表，ts按tf格式化
+func queryLatestValues(ctx context.Context, deviceIds []string, keys []string, tf *timeutil.Formatter) (map[string][]map[string]interface{}, error) {
+	latest, err := db.GetLatest(ctx, deviceIds, keys)
+	if err != nil {
+		return nil, err
+	}
 
-		if _, ok := mp["k"]; ok {
-			m["key"] = mp["k"]
+	var retMap = make(map[string][]map[string]interface{}, len(deviceIds))
+	for _, deviceId := range deviceIds {
+		retMap[deviceId] = make([]map[string]interface{}, 0)
+	}
+	var dataMap = make([]map[string]interface{}, 0)
+	for _, deviceId := range deviceIds {
+		for _, v := range latest[deviceId] {
+			mp := v.Map()
+			mp["device_id"] = deviceId
+			dataMap = append(dataMap, mp)
 		}
-
-		m["device_id"] = deviceId
-
-		if _, ok := mp["tenant_id"]; ok {
-			m["tenant_id"] = mp["tenant_id"]
+	}
+	for _, mp := range dataMap {
+		deviceId := fmt.Sprintf("%v", mp["device_id"])
+		m, err := currentRow(mp, tf)
+		if err != nil {
+			return nil, err
 		}
+		retMap[deviceId] = append(retMap[deviceId], m)
+	}
+	return retMap, nil
+}
 
-		if len(m) > 0 {
-			dataMapList = append(dataMapList, m)
+// 当前值的一行：去掉占位值，ts按tf格式化，k输出为key
+func currentRow(mp map[string]interface{}, tf *timeutil.Formatter) (map[string]interface{}, error) {
+	m := historyRow(mp)
+	if ts, ok := mp["ts"]; ok && ts != "" {
+		t, err := timeutil.Parse(ts)
+		if err != nil {
+			return nil, err
 		}
+		m["ts"] = tf.Format(t)
 	}
-
-	// 将map转成json
-	dataJson, err := json.Marshal(dataMapList)
-	if err != nil {
-		return nil, err
+	if tenantId, ok := mp["tenant_id"]; ok {
+		m["tenant_id"] = tenantId
 	}
-	log.Println("dataJson: ", string(dataJson))
-	return &pb.GetDeviceAttributesCurrentListReply{Status: 1, Message: "", Data: string(dataJson)}, nil
+	return m, nil
 }
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	"thingspanel-TDengine/timeutil"
+)
+
+func TestCurrentRow(t *testing.T) {
+	tf, err := timeutil.New("UTC", "ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := time.UnixMilli(1697767200000)
+	tests := []struct {
+		name  string
+		value db.LatestValue
+		want  map[string]interface{}
+	}{
+		{
+			name:  "number",
+			value: db.LatestValue{Ts: ts, Key: "temp", BoolV: db.BoolDefault, NumberV: 21.5, StringV: db.StringDefault, TenantId: "t1"},
+			want:  map[string]interface{}{"key": "temp", "number_v": 21.5, "ts": int64(1697767200000), "tenant_id": "t1"},
+		},
+		{
+			name:  "string",
+			value: db.LatestValue{Ts: ts, Key: "mode", BoolV: db.BoolDefault, NumberV: db.NumberDefault, StringV: "auto"},
+			want:  map[string]interface{}{"key": "mode", "string_v": "auto", "ts": int64(1697767200000), "tenant_id": ""},
+		},
+		{
+			name:  "bool",
+			value: db.LatestValue{Ts: ts, Key: "door", BoolV: 0, NumberV: db.NumberDefault, StringV: db.StringDefault},
+			want:  map[string]interface{}{"key": "door", "bool_v": 0, "ts": int64(1697767200000), "tenant_id": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := currentRow(tt.value.Map(), tf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
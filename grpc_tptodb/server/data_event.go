@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 越限条件：start为事件开始条件，end为结束条件(%s为阈值±回差)，peak为峰值函数
+type thresholdOp struct {
+	start string
+	end   string
+	sign  float64 // 回差方向，0表示不支持回差
+	peak  string
+}
+
+var thresholdOps = map[string]thresholdOp{
+	">":  {start: "number_v > %s", end: "number_v <= %s", sign: -1, peak: "MAX"},
+	">=": {start: "number_v >= %s", end: "number_v < %s", sign: -1, peak: "MAX"},
+	"<":  {start: "number_v < %s", end: "number_v >= %s", sign: 1, peak: "MIN"},
+	"<=": {start: "number_v <= %s", end: "number_v > %s", sign: 1, peak: "MIN"},
+	"==": {start: "number_v = %s", end: "number_v <> %s", peak: "MAX"},
+	"!=": {start: "number_v <> %s", end: "number_v = %s", peak: "MAX"},
+}
+
+// 越限事件
+type thresholdEvent struct {
+	Start time.Time
+	End   time.Time
+	Peak  interface{}
+	Mean  interface{}
+	Open  bool
+}
+
+// 阈值越限事件查询：用EVENT_WINDOW划分事件，查询结束时仍未恢复的事件单独补查
+func (s *server) GetDeviceThresholdEvents(ctx context.Context, in *pb.GetDeviceThresholdEventsRequest) (*pb.GetDeviceThresholdEventsReply, error) {
+	deviceId := in.GetDeviceId()
+	key := in.GetKey()
+	if deviceId == "" || key == "" {
+		return nil, status.Error(codes.InvalidArgument, "device_id and key are required")
+	}
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	if err := checkTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+	op, startCond, endCond, err := thresholdConds(in.GetOp(), in.GetThreshold(), in.GetHysteresis())
+	if err != nil {
+		return nil, err
+	}
+	if in.GetMinDuration() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "min_duration must be non-negative")
+	}
+
+	// 持续中的事件截止到查询结束时间，结束时间在未来时截止到当前时间
+	until := endTime
+	if now := time.Now(); until.After(now) {
+		until = now
+	}
+	events, err := queryThresholdEvents(ctx, deviceId, key, startTime, endTime, until, op.peak, startCond, endCond)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query threshold events", "key", key, "error", err)
+		return nil, err
+	}
+
+	minDuration := time.Duration(in.GetMinDuration()) * time.Millisecond
+	dataList := make([]map[string]interface{}, 0, len(events))
+	for _, e := range events {
+		if e.End.Sub(e.Start) < minDuration {
+			continue
+		}
+		dataList = append(dataList, map[string]interface{}{
+			"start":    tf.Format(e.Start),
+			"end":      tf.Format(e.End),
+			"duration": e.End.Sub(e.Start).Milliseconds(),
+			"peak":     e.Peak,
+			"mean":     e.Mean,
+			"open":     e.Open,
+		})
+	}
+
+	jsonStr, err := json.Marshal(dataList)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal threshold events", "error", err)
+		return &pb.GetDeviceThresholdEventsReply{Status: 1, Message: err.Error(), Data: string("[]")}, nil
+	}
+	logger.FromContext(ctx).Debug("GetDeviceThresholdEvents", "key", key, "events", len(events), "matched", len(dataList))
+	return &pb.GetDeviceThresholdEventsReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+}
+
+// 查询越限事件，按开始时间排序；已结束事件的end为满足结束条件的数据时间，统计值包含该点
+func queryThresholdEvents(ctx context.Context, deviceId, key string, start, end, until time.Time, peak, startCond, endCond string) ([]thresholdEvent, error) {
+	finder := zorm.NewFinder()
+	// 超级表上的EVENT_WINDOW需要按子表分区，设备只在一个子表中
+	finder.Append(fmt.Sprintf("SELECT _wstart AS ws,_wend AS we,%s(number_v) AS peak,AVG(number_v) AS mean FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? AND number_v <> %s PARTITION BY tbname EVENT_WINDOW START WITH %s END WITH %s ORDER BY ws",
+		peak, db.DBName, db.SuperTableTv, formatNumber(db.NumberDefault), startCond, endCond), deviceId, key, start, end)
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	events := make([]thresholdEvent, 0, len(dataMap)+1)
+	after := start
+	for _, v := range dataMap {
+		ws, err := timeutil.Parse(v["ws"])
+		if err != nil {
+			return nil, err
+		}
+		we, err := timeutil.Parse(v["we"])
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, thresholdEvent{Start: ws, End: we, Peak: v["peak"], Mean: v["mean"]})
+		after = we.Add(time.Microsecond)
+	}
+
+	// 最后一个事件之后仍有满足开始条件的数据，说明事件尚未结束
+	finder = zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT FIRST(ts) AS ws FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? AND number_v <> %s AND %s",
+		db.DBName, db.SuperTableTv, formatNumber(db.NumberDefault), startCond), deviceId, key, after, end)
+	finder.Append(rowLimit())
+	openMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(openMap)); err != nil {
+		return nil, err
+	}
+	if len(openMap) == 0 || openMap[0]["ws"] == nil {
+		return events, nil
+	}
+	ws, err := timeutil.Parse(openMap[0]["ws"])
+	if err != nil {
+		return nil, err
+	}
+	finder = zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT %s(number_v) AS peak,AVG(number_v) AS mean FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? AND number_v <> %s",
+		peak, db.DBName, db.SuperTableTv, formatNumber(db.NumberDefault)), deviceId, key, ws, end)
+	finder.Append(rowLimit())
+	statMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(statMap)); err != nil {
+		return nil, err
+	}
+	e := thresholdEvent{Start: ws, End: until, Open: true}
+	if e.End.Before(ws) {
+		e.End = ws
+	}
+	if len(statMap) > 0 {
+		e.Peak, e.Mean = statMap[0]["peak"], statMap[0]["mean"]
+	}
+	return append(events, e), nil
+}
+
+// 生成事件开始和结束条件，阈值及阈值±回差必须是有限值，否则拼出的SQL无法执行
+func thresholdConds(name string, threshold, hysteresis float64) (thresholdOp, string, string, error) {
+	op, ok := thresholdOps[name]
+	if !ok {
+		return op, "", "", status.Errorf(codes.InvalidArgument, "unsupported op %q, expected one of >, >=, <, <=, ==, !=", name)
+	}
+	if !isFinite(threshold) {
+		return op, "", "", status.Error(codes.InvalidArgument, "threshold must be a finite number")
+	}
+	if !isFinite(hysteresis) || hysteresis < 0 || (op.sign == 0 && hysteresis != 0) {
+		return op, "", "", status.Error(codes.InvalidArgument, "hysteresis must be non-negative and is only supported with >, >=, <, <=")
+	}
+	end := threshold + op.sign*hysteresis
+	if !isFinite(end) {
+		return op, "", "", status.Error(codes.InvalidArgument, "threshold with hysteresis must be a finite number")
+	}
+	return op, fmt.Sprintf(op.start, formatNumber(threshold)), fmt.Sprintf(op.end, formatNumber(end)), nil
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// 数值写入SQL条件
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
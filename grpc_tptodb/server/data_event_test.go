@@ -0,0 +1,71 @@
+package server
+
+import (
+	"math"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestThresholdConds(t *testing.T) {
+	tests := []struct {
+		name       string
+		op         string
+		threshold  float64
+		hysteresis float64
+		wantStart  string
+		wantEnd    string
+		wantPeak   string
+	}{
+		{name: "greater", op: ">", threshold: 30, wantStart: "number_v > 30", wantEnd: "number_v <= 30", wantPeak: "MAX"},
+		{name: "greater with hysteresis", op: ">", threshold: 30, hysteresis: 2, wantStart: "number_v > 30", wantEnd: "number_v <= 28", wantPeak: "MAX"},
+		{name: "greater or equal with hysteresis", op: ">=", threshold: 30, hysteresis: 0.5, wantStart: "number_v >= 30", wantEnd: "number_v < 29.5", wantPeak: "MAX"},
+		{name: "less with hysteresis", op: "<", threshold: -10, hysteresis: 2, wantStart: "number_v < -10", wantEnd: "number_v >= -8", wantPeak: "MIN"},
+		{name: "less or equal", op: "<=", threshold: 1.25, wantStart: "number_v <= 1.25", wantEnd: "number_v > 1.25", wantPeak: "MIN"},
+		{name: "equal", op: "==", threshold: 1, wantStart: "number_v = 1", wantEnd: "number_v <> 1", wantPeak: "MAX"},
+		{name: "not equal", op: "!=", threshold: 0, wantStart: "number_v <> 0", wantEnd: "number_v = 0", wantPeak: "MAX"},
+		// 大数不使用科学计数法
+		{name: "large threshold", op: ">", threshold: 1e21, wantStart: "number_v > 1000000000000000000000", wantEnd: "number_v <= 1000000000000000000000", wantPeak: "MAX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, start, end, err := thresholdConds(tt.op, tt.threshold, tt.hysteresis)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd || op.peak != tt.wantPeak {
+				t.Fatalf("got (%q, %q, %s), want (%q, %q, %s)", start, end, op.peak, tt.wantStart, tt.wantEnd, tt.wantPeak)
+			}
+		})
+	}
+}
+
+func TestThresholdCondsInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		op         string
+		threshold  float64
+		hysteresis float64
+	}{
+		{name: "unknown op", op: "=>", threshold: 1},
+		{name: "nan threshold", op: ">", threshold: math.NaN()},
+		{name: "inf threshold", op: ">", threshold: math.Inf(1)},
+		{name: "negative inf threshold", op: "<", threshold: math.Inf(-1)},
+		{name: "nan hysteresis", op: ">", threshold: 1, hysteresis: math.NaN()},
+		{name: "inf hysteresis", op: ">", threshold: 1, hysteresis: math.Inf(1)},
+		{name: "negative hysteresis", op: ">", threshold: 1, hysteresis: -1},
+		{name: "hysteresis with equal", op: "==", threshold: 1, hysteresis: 1},
+		// 阈值和回差都有限，但相减后溢出
+		{name: "hysteresis overflows", op: ">", threshold: -math.MaxFloat64, hysteresis: math.MaxFloat64},
+		{name: "hysteresis overflows upward", op: "<", threshold: math.MaxFloat64, hysteresis: math.MaxFloat64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := thresholdConds(tt.op, tt.threshold, tt.hysteresis)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("err = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
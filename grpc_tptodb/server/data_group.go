@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 分组方式对应的分区列
+// 子表按设备id前缀划分，一个子表可能包含多个设备，所以按device_id列而不是tbname分区
+var groupColumns = map[string]string{
+	"device": "device_id",
+}
+
+// 按模型查询或分组时device_model的最多设备数
+const maxModelDevices = 1000
+
+// 按模型筛选设备：指定device_id时每个设备都须在device_model中，否则取device_model中的全部设备，
+// modelId不为空时只保留该模型的设备，返回的设备按id排序
+func modelDevices(deviceIds []string, deviceModel map[string]string, modelId string) ([]string, error) {
+	if len(deviceModel) == 0 {
+		return nil, fmt.Errorf("device_model is required when querying or grouping by model")
+	}
+	if len(deviceModel) > maxModelDevices {
+		return nil, fmt.Errorf("too many devices in device_model, at most %d are allowed", maxModelDevices)
+	}
+	for deviceId, model := range deviceModel {
+		if deviceId == "" || model == "" {
+			return nil, fmt.Errorf("device_model entries must have a device id and a model id")
+		}
+	}
+	candidates := deviceIds
+	if len(candidates) == 0 {
+		for deviceId := range deviceModel {
+			candidates = append(candidates, deviceId)
+		}
+	}
+	seen := make(map[string]bool, len(candidates))
+	selected := make([]string, 0, len(candidates))
+	for _, deviceId := range candidates {
+		model, ok := deviceModel[deviceId]
+		if !ok {
+			return nil, fmt.Errorf("device %s has no model in device_model", deviceId)
+		}
+		if seen[deviceId] || (modelId != "" && model != modelId) {
+			continue
+		}
+		seen[deviceId] = true
+		selected = append(selected, deviceId)
+	}
+	sort.Strings(selected)
+	return selected, nil
+}
+
+// 把设备映射为模型id的分区表达式，参数按设备顺序绑定
+func modelCase(deviceIds []string, deviceModel map[string]string) (string, []interface{}) {
+	var b strings.Builder
+	args := make([]interface{}, 0, 2*len(deviceIds))
+	b.WriteString("CASE device_id")
+	for _, deviceId := range deviceIds {
+		b.WriteString(" WHEN ? THEN ?")
+		args = append(args, deviceId, deviceModel[deviceId])
+	}
+	b.WriteString(" END")
+	return b.String(), args
+}
+
+// 跨设备分组聚合
+func (s *server) GetGroupKVDataWithAggregate(ctx context.Context, in *pb.GetGroupKVDataWithAggregateRequest) (*pb.GetGroupKVDataWithAggregateReply, error) {
+	deviceIds := in.GetDeviceId()
+	tenantId := in.GetTenantId()
+	modelId := in.GetModelId()
+	logger.FromContext(ctx).Debug("GetGroupKVDataWithAggregate", "devices", len(deviceIds), "tenant_id", tenantId, "model_id", modelId, "key", in.GetKey(), "group_by", in.GetGroupBy())
+
+	// 开启认证时只能查询本租户的数据
+	if tenant, ok := tenantFromContext(ctx); ok && tenant != allTenants {
+		if tenantId != "" && tenantId != tenant {
+			return nil, status.Error(codes.PermissionDenied, "tenant_id does not match caller")
+		}
+		tenantId = tenant
+	}
+	if len(deviceIds) == 0 && tenantId == "" && modelId == "" {
+		return nil, status.Error(codes.InvalidArgument, "one of device_id, tenant_id or model_id is required")
+	}
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	if err := checkTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	window := in.GetAggregateWindow()
+	if window < 0 {
+		return nil, status.Error(codes.InvalidArgument, "aggregate_window must not be negative")
+	}
+	if err := checkWindows(startTime, endTime, window); err != nil {
+		return nil, err
+	}
+
+	// 按模型分组时分区列为device_id到模型id的CASE表达式，SELECT和PARTITION BY中各绑定一次参数
+	var groupCol string
+	var groupArgs []interface{}
+	byModel := in.GetGroupBy() == "model"
+	if byModel || modelId != "" {
+		var err error
+		if deviceIds, err = modelDevices(deviceIds, in.GetDeviceModel(), modelId); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if len(deviceIds) == 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "no device of model %s in device_model", modelId)
+		}
+	}
+	if byModel {
+		groupCol, groupArgs = modelCase(deviceIds, in.GetDeviceModel())
+	} else if in.GetGroupBy() != "" {
+		var ok bool
+		if groupCol, ok = groupColumns[in.GetGroupBy()]; !ok {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("unsupported group_by %q, expected device or model", in.GetGroupBy()))
+		}
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+	selects, err := parseAggregateFuncs(in.GetAggregateFuncs())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := checkMultiTable(selects); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	fill, err := fillClause(in.GetFill(), in.GetFillValue(), len(selects))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// SELECT [_wstart,_wend,][分组列,]聚合列 ... [PARTITION BY 分组列] [INTERVAL ... FILL]
+	columns := aggregateColumns(selects)
+	if groupCol != "" {
+		columns = fmt.Sprintf("%s AS g,%s", groupCol, columns)
+	}
+	if window > 0 {
+		columns = "_wstart AS ws,_wend AS we," + columns
+	}
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT %s FROM %s.%s WHERE k = ? AND ts >= ? AND ts <= ?", columns, db.DBName, db.SuperTableTv),
+		append(append([]interface{}{}, groupArgs...), in.GetKey(), startTime, endTime)...)
+	if len(deviceIds) > 0 {
+		finder.Append("AND device_id in (?)", deviceIds)
+	}
+	if tenantId != "" {
+		finder.Append("AND tenant_id = ?", tenantId)
+	}
+	if groupCol != "" {
+		finder.Append("PARTITION BY "+groupCol, groupArgs...)
+	}
+	if window > 0 {
+		finder.Append(fmt.Sprintf("INTERVAL(%da) %s", window, fill))
+	}
+	finder.Append(rowLimit())
+
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query group aggregate", "error", err)
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]aggregateBucket)
+	for _, v := range dataMap {
+		b := aggregateBucket{Start: startTime, End: endTime, Values: make([]interface{}, len(selects))}
+		if window > 0 {
+			if b.Start, err = timeutil.Parse(v["ws"]); err != nil {
+				return nil, err
+			}
+			if b.End, err = timeutil.Parse(v["we"]); err != nil {
+				return nil, err
+			}
+		}
+		for i := range selects {
+			b.Values[i] = v[fmt.Sprintf("v%d", i)]
+		}
+		var group string
+		if groupCol != "" {
+			group = fmt.Sprintf("%v", v["g"])
+		}
+		groups[group] = append(groups[group], b)
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var dataList = make([]map[string]interface{}, 0, len(groups))
+	for _, name := range names {
+		buckets := groups[name]
+		sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+		points := make([]map[string]interface{}, 0, len(buckets))
+		for _, b := range buckets {
+			p := map[string]interface{}{"x": tf.Format(b.Start), "x2": tf.Format(b.End)}
+			for i, sel := range selects {
+				p[sel.Name] = b.Values[i]
+			}
+			points = append(points, p)
+		}
+		dataList = append(dataList, map[string]interface{}{"group": name, "points": points})
+	}
+
+	dataJson, err := json.Marshal(dataList)
+	if err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).Debug("response", "data", string(dataJson))
+	return &pb.GetGroupKVDataWithAggregateReply{Status: 1, Message: "", Data: string(dataJson)}, nil
+}
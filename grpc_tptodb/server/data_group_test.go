@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestModelDevices(t *testing.T) {
+	deviceModel := map[string]string{"d1": "m1", "d2": "m2", "d3": "m1"}
+	tests := []struct {
+		name        string
+		deviceIds   []string
+		deviceModel map[string]string
+		modelId     string
+		want        []string
+		wantErr     bool
+	}{
+		{name: "all mapped devices", deviceModel: deviceModel, want: []string{"d1", "d2", "d3"}},
+		{name: "devices of one model", deviceModel: deviceModel, modelId: "m1", want: []string{"d1", "d3"}},
+		{name: "intersect with device list", deviceIds: []string{"d3", "d2", "d3"}, deviceModel: deviceModel, modelId: "m1", want: []string{"d3"}},
+		{name: "unknown model selects nothing", deviceModel: deviceModel, modelId: "m9", want: []string{}},
+		{name: "device without model", deviceIds: []string{"d1", "d4"}, deviceModel: deviceModel, wantErr: true},
+		{name: "missing mapping", modelId: "m1", wantErr: true},
+		{name: "empty model id in mapping", deviceModel: map[string]string{"d1": ""}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := modelDevices(tt.deviceIds, tt.deviceModel, tt.modelId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelDevicesTooMany(t *testing.T) {
+	deviceModel := make(map[string]string, maxModelDevices+1)
+	for i := 0; i <= maxModelDevices; i++ {
+		deviceModel[fmt.Sprintf("d%d", i)] = "m1"
+	}
+	if _, err := modelDevices(nil, deviceModel, ""); err == nil {
+		t.Fatal("expected too many devices error")
+	}
+}
+
+func TestModelCase(t *testing.T) {
+	expr, args := modelCase([]string{"d1", "d2"}, map[string]string{"d1": "m1", "d2": "m2"})
+	if want := "CASE device_id WHEN ? THEN ? WHEN ? THEN ? END"; expr != want {
+		t.Fatalf("expr = %q, want %q", expr, want)
+	}
+	if want := []interface{}{"d1", "m1", "d2", "m2"}; !reflect.DeepEqual(args, want) {
+		t.Fatalf("args = %v, want %v", args, want)
+	}
+}
@@ -4,174 +4,183 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"sort"
 	"time"
 
 	db "thingspanel-TDengine/db"
 	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
 
 	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // SayHello implements helloworld.GreeterServer
 func (s *server) SayHello(ctx context.Context, in *pb.HelloRequest) (*pb.HelloReply, error) {
-	log.Printf("Received: %v", in.GetName())
+	logger.FromContext(ctx).Debug("SayHello", "name", in.GetName())
 	return &pb.HelloReply{Message: "Hello " + in.GetName()}, nil
 }
 
 // 设备数据历史记录
 func (s *server) GetDeviceAttributesHistory(ctx context.Context, in *pb.GetDeviceAttributesHistoryRequest) (*pb.GetDeviceAttributesHistoryReply, error) {
 	// 时间是毫秒数字时间戳，需要转成time.Time
-	startTime := time.Unix(0, in.GetStartTime()*int64(time.Millisecond))
-	endTime := time.Unix(0, in.GetEndTime()*int64(time.Millisecond))
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Print("st: ", startTime.String())
-	log.Print("ed: ", endTime.String())
+	logger.FromContext(ctx).Debug("GetDeviceAttributesHistory", "start", startTime, "end", endTime)
 
+	if err := checkTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	tolerance := in.GetTolerance()
+	if tolerance < 0 {
+		return nil, status.Error(codes.InvalidArgument, "tolerance must not be negative")
+	}
+	// 指定limit时每个属性最多返回limit条，否则超过最大行数时返回错误
+	// 原实现中limit默认10但从未用于查询，未指定limit时一直返回全部数据，这里保持不截断
 	var limit int64 = in.GetLimit()
-	if limit <= 0 {
-		limit = 10
+	limitClause := rowLimit()
+	if limit > 0 && limit <= int64(maxRows()) {
+		limitClause = fmt.Sprintf("LIMIT %d", limit)
 	}
 
-	var dataSlice [][]map[string]interface{}
-	var attributeList []string = in.GetAttribute()
-	finder := zorm.NewFinder()
+	// 去掉空key、systime和重复的key
+	var attributeList []string
+	seen := make(map[string]bool)
+	for _, v := range in.GetAttribute() {
+		if v != "" && v != "systime" && !seen[v] {
+			seen[v] = true
+			attributeList = append(attributeList, v)
+		}
+	}
 
-	// 用indexList记录dataSlice中的每个list中的下标,初始化每个list的下标为0
-	var indexList []int
-	var err error
+	// 未指定属性但指定了limit时先查出时间范围内的key，再按属性逐个查询，避免数据多的key占满limit
+	if len(attributeList) == 0 && limit > 0 {
+		finder := zorm.NewFinder()
+		finder.Append(fmt.Sprintf("SELECT DISTINCT k FROM %s.%s WHERE device_id = ? AND ts >= ? AND ts <= ?",
+			db.DBName, db.SuperTableTv), in.GetDeviceId(), startTime, endTime)
+		finder.Append(rowLimit())
+		keyList, err := db.QueryMap(ctx, finder, nil)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to get keys from ts_kv", "error", err)
+			return nil, err
+		}
+		if err := checkRows(len(keyList)); err != nil {
+			return nil, err
+		}
+		for _, mp := range keyList {
+			attributeList = append(attributeList, fmt.Sprintf("%v", mp["k"]))
+		}
+		sort.Strings(attributeList)
+	}
 
+	var columns [][]alignedPoint
 	if len(attributeList) > 0 {
-		// 遍历Attributes
+		// 每个属性单独查询，limit对每个属性生效
 		for _, v := range attributeList {
-			if v == "" || v == "systime" {
-				continue
-			}
-			indexList = append(indexList, 0)
-			// 获取每个属性的历史数据列表
-			var dataList []map[string]interface{}
-
+			finder := zorm.NewFinder()
 			finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? order by ts asc",
 				db.DBName, db.SuperTableTv), in.GetDeviceId(), v, startTime, endTime)
+			finder.Append(limitClause)
 
-			page := zorm.NewPage()
-			page.PageNo = 1            // 查询第1页,默认是1
-			page.PageSize = int(limit) // 每页20条,默认是20
-
-			dataList, err = zorm.QueryMap(ctx, finder, nil)
+			dataList, err := db.QueryMap(ctx, finder, nil)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to get data from ts_kv", "error", err)
+				return nil, err
+			}
+			if err := checkRows(len(dataList)); err != nil {
+				return nil, err
+			}
+			column, err := alignedColumn(dataList)
 			if err != nil {
-				log.Printf("Failed to get data from ts_kv: %v", err)
 				return nil, err
 			}
-			dataSlice = append(dataSlice, dataList)
+			columns = append(columns, column)
 		}
 	} else {
-		indexList = append(indexList, 0)
-		// 获取每个属性的历史数据列表
-		var dataList []map[string]interface{}
-
+		// 未指定属性和limit时一次查询全部key，按key拆分成列
+		finder := zorm.NewFinder()
 		finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND ts >= ? AND ts <= ? order by ts asc",
 			db.DBName, db.SuperTableTv), in.GetDeviceId(), startTime, endTime)
+		finder.Append(limitClause)
 
-		page := zorm.NewPage()
-		page.PageNo = 1            // 查询第1页,默认是1
-		page.PageSize = int(limit) // 每页20条,默认是20
-
-		dataList, err = zorm.QueryMap(ctx, finder, nil)
+		dataList, err := db.QueryMap(ctx, finder, nil)
 		if err != nil {
-			log.Printf("Failed to get data from ts_kv: %v", err)
+			logger.FromContext(ctx).Error("failed to get data from ts_kv", "error", err)
 			return nil, err
 		}
-		dataSlice = append(dataSlice, dataList)
-	}
-
-	var dataMap = make(map[string][]interface{})
-
-	// 每个列的时间戳数组
-	for {
-		var nullCount int
-		// 清空tsList
-		var tsList []interface{}
-		// 取当前下标里数据的ts,如果都为空值，则跳出循环
-		for i, v := range indexList {
-
-			// 超过或等于indexList数据长度的下标赋空值
-			if v < len(dataSlice[i]) {
-				tsList = append(tsList, dataSlice[i][indexList[i]]["ts"])
-			} else {
-				tsList = append(tsList, nil)
-				nullCount++
-			}
-		}
-		if nullCount == len(indexList) {
-			break
+		if err := checkRows(len(dataList)); err != nil {
+			return nil, err
 		}
-		// 判断tsList哪个下标的ts最小
-		minIndex := 0
-		for i := 1; i < len(tsList); i++ {
-			// tsList为空值的下标不参与比较
-			if tsList[i] != nil {
-				if tsList[i].(time.Time).Before(tsList[minIndex].(time.Time)) {
-					minIndex = i
-				}
+		byKey := make(map[string][]map[string]interface{})
+		for _, mp := range dataList {
+			k := fmt.Sprintf("%v", mp["k"])
+			if _, ok := byKey[k]; !ok {
+				attributeList = append(attributeList, k)
 			}
+			byKey[k] = append(byKey[k], mp)
 		}
-		// fmt.Println("--------------------------", tsList)
-		// tsList中相等的ts的下标都存入dataMap
-		for i, v := range tsList {
-			//自己不和自己比较
-			if i == minIndex {
-				// 只在存最小的ts时存systime
-				// 格式化时间
-				dataMap["systime"] = append(dataMap["systime"], v.(time.Time).Format("2006-01-02 15:04:05"))
-				//直接赋值
-				// 判断是否是字符串
-				if dataSlice[i][indexList[i]]["string_v"].(string) != "" {
-					dataMap[attributeList[i]] = append(dataMap[attributeList[i]], dataSlice[i][indexList[i]]["string_v"].(string))
-				} else {
-					dataMap[attributeList[i]] = append(dataMap[attributeList[i]], dataSlice[i][indexList[i]]["number_v"].(float64))
-				}
-				//下标加1
-				indexList[i]++
-			} else {
-				// tsList为空值的下标直接赋空值
-				if v != nil {
-					// 判断是否有相等的ts
-					if v.(time.Time).Equal(tsList[minIndex].(time.Time)) {
-						// 判断是否是字符串
-						if dataSlice[i][indexList[i]]["string_v"].(string) != "" {
-							dataMap[attributeList[i]] = append(dataMap[attributeList[i]], dataSlice[i][indexList[i]]["string_v"].(string))
-						} else {
-							dataMap[attributeList[i]] = append(dataMap[attributeList[i]], dataSlice[i][indexList[i]]["number_v"].(float64))
-						}
-						//下标加1
-						indexList[i]++
-					} else {
-						// 添加空值
-						dataMap[attributeList[i]] = append(dataMap[attributeList[i]], nil)
-					}
-				} else {
-					// 添加空值
-					dataMap[attributeList[i]] = append(dataMap[attributeList[i]], nil)
-				}
+		for _, k := range attributeList {
+			column, err := alignedColumn(byKey[k])
+			if err != nil {
+				return nil, err
 			}
+			columns = append(columns, column)
+		}
+	}
+
+	times, cells, present := alignColumns(columns, time.Duration(tolerance)*time.Millisecond)
+	if err := fillAligned(times, cells, present, in.GetFill(), in.GetFillValue()); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var dataMap = make(map[string][]interface{})
+	for _, t := range times {
+		dataMap["systime"] = append(dataMap["systime"], tf.Format(t))
+	}
+	if len(times) > 0 {
+		for i, k := range attributeList {
+			dataMap[k] = cells[i]
 		}
 	}
 	// 将dataMap转成json字符串
 	jsonStr, err := json.Marshal(dataMap)
 	if err != nil {
-		log.Printf("Failed to marshal dataMap: %v", err)
+		logger.FromContext(ctx).Error("failed to marshal dataMap", "error", err)
 		return nil, err
 	}
-	fmt.Println("dataMap", dataMap)
+	logger.FromContext(ctx).Debug("response", "data", string(jsonStr))
 	return &pb.GetDeviceAttributesHistoryReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
 }
 
+// 把一个key的查询结果转成带类型的列，占位值的行跳过
+func alignedColumn(dataList []map[string]interface{}) ([]alignedPoint, error) {
+	column := make([]alignedPoint, 0, len(dataList))
+	for _, mp := range dataList {
+		value, ok := typedValue(mp)
+		if !ok {
+			continue
+		}
+		ts, err := timeutil.Parse(mp["ts"])
+		if err != nil {
+			return nil, err
+		}
+		column = append(column, alignedPoint{ts: ts, value: value})
+	}
+	return column, nil
+}
+
 // 设备历史数据记录(多条)
 func (s *server) GetDeviceHistory(ctx context.Context, in *pb.GetDeviceHistoryRequest) (*pb.GetDeviceHistoryReply, error) {
 	// 时间是毫秒数字时间戳，需要转成time.Time
-	startTime := time.Unix(0, in.GetStartTime()*int64(time.Microsecond))
-	endTime := time.Unix(0, in.GetEndTime()*int64(time.Millisecond))
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
 	key := in.Key
 	// 最长查询时间间隔为30天，超过100天默认查询30天
 	if endTime.Sub(startTime) > 30*24*time.Hour {
@@ -179,33 +188,35 @@ func (s *server) GetDeviceHistory(ctx context.Context, in *pb.GetDeviceHistoryRe
 	}
 
 	var deviceId string = in.GetDeviceId()
-	log.Printf("request:%+v", in)
-	log.Printf("st:%+v ed:%+v", startTime.String(), endTime.String())
-	var err error
+	logger.FromContext(ctx).Debug("GetDeviceHistory", "key", key, "start", startTime, "end", endTime)
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
 
 	var dataMapList []map[string]interface{}
 	// 查询表ts_kv，获取总数
 	if len(key) > 0 {
 		if key == "" {
 			// 提示不支持
-			log.Printf("total is 0")
+			logger.FromContext(ctx).Warn("empty key is not supported")
 			return &pb.GetDeviceHistoryReply{Status: 0, Message: "Not supported", Data: ""}, nil
 		}
 
 		finder := zorm.NewFinder()
 		finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? order by ts desc",
 			db.DBName, db.SuperTableTv), deviceId, in.GetKey(), startTime, endTime)
-
-		// page := zorm.NewPage()
-		// page.PageNo = 1            // 查询第1页,默认是1
-		// page.PageSize = int(limit) // 每页20条,默认是20
+		finder.Append(rowLimit())
 
 		// 执行查询
-		dataMapList, err = zorm.QueryMap(ctx, finder, nil)
+		dataMapList, err = db.QueryMap(ctx, finder, nil)
 		if err != nil { // 标记测试失败
-			log.Printf("Failed to get total from ts_kv")
+			logger.FromContext(ctx).Error("failed to get total from ts_kv", "error", err)
 			return &pb.GetDeviceHistoryReply{Status: 0, Message: "Failed to get total from ts_kv", Data: ""}, nil
 		}
+		if err := checkRows(len(dataMapList)); err != nil {
+			return nil, err
+		}
 	}
 
 	var retMapList []map[string]interface{}
@@ -230,13 +241,12 @@ func (s *server) GetDeviceHistory(ctx context.Context, in *pb.GetDeviceHistoryRe
 		}
 
 		if ts, ok := mp["ts"]; ok && ts != "" {
-			// 解析时间字符串
-			t, err := time.Parse(layout, fmt.Sprintf("%s", ts))
+			t, err := timeutil.Parse(ts)
 			if err != nil {
-				log.Println("Error parsing time:", err)
+				logger.FromContext(ctx).Error("failed to parse time", "error", err)
 				return nil, err
 			}
-			m["ts"] = t.UnixNano() / int64(time.Millisecond)
+			m["ts"] = tf.Format(t)
 		}
 
 		m["device_id"] = in.GetDeviceId()
@@ -252,15 +262,20 @@ func (s *server) GetDeviceHistory(ctx context.Context, in *pb.GetDeviceHistoryRe
 	// 将map转成json
 	dataJson, err := json.Marshal(retMapList)
 	if err != nil {
-		log.Printf("Failed to marshal dataMap: %v", err)
+		logger.FromContext(ctx).Error("failed to marshal dataMap", "error", err)
 		return &pb.GetDeviceHistoryReply{Status: 0, Message: "Failed to marshal dataMap", Data: ""}, nil
 	}
 
-	log.Println("dataJson: ", string(dataJson))
+	logger.FromContext(ctx).Debug("response", "data", string(dataJson))
 	return &pb.GetDeviceHistoryReply{Status: 1, Message: "", Data: string(dataJson)}, nil
 }
 
 func (s *server) GetDeviceHistoryWithPageAndPage(ctx context.Context, in *pb.GetDeviceHistoryWithPageAndPageRequest) (*pb.GetDeviceHistoryWithPageAndPageReply, error) {
+	// 未使用旧的first_data_time/end_data_time翻页参数时按游标分页
+	if in.GetFirstDataTime() == 0 && in.GetEndDataTime() == 0 {
+		return historyPageByCursor(ctx, in)
+	}
+
 	var baseQuery string
 	startTime := in.GetStartTime()
 	endTime := in.GetEndTime()
@@ -282,22 +297,28 @@ func (s *server) GetDeviceHistoryWithPageAndPage(ctx context.Context, in *pb.Get
 		endTime = firstDataTime
 	}
 
-	log.Printf("request:%+v", in)
-
-	//纳秒转成时间
-	startTime2 := time.Unix(0, startTime*int64(time.Millisecond))
-	endTime2 := time.Unix(0, endTime*int64(time.Millisecond))
+	//毫秒转成时间
+	startTime2 := timeutil.FromMillis(startTime)
+	endTime2 := timeutil.FromMillis(endTime)
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
 
-	log.Printf("st: %+v ed: %+v", startTime2.String(), endTime2.String())
+	logger.FromContext(ctx).Debug("GetDeviceHistoryWithPageAndPage", "key", in.GetKey(), "start", startTime2, "end", endTime2)
 
 	finder := zorm.NewFinder()
 	finder.Append(fmt.Sprintf(baseQuery, db.DBName, db.SuperTableTv), in.GetDeviceId(), in.GetKey(), startTime2, endTime2)
+	finder.Append(rowLimit())
 
-	result, err := zorm.QueryMap(ctx, finder, nil)
+	result, err := db.QueryMap(ctx, finder, nil)
 	if err != nil {
-		log.Printf("Failed to QueryMap err: %v\n", err)
+		logger.FromContext(ctx).Error("failed to query history", "error", err)
 		return &pb.GetDeviceHistoryWithPageAndPageReply{Status: 0, Message: "Failed to QueryMap", Data: ""}, nil
 	}
+	if err := checkRows(len(result)); err != nil {
+		return nil, err
+	}
 
 	var retMapList []map[string]interface{}
 	for _, mp := range result {
@@ -321,13 +342,12 @@ func (s *server) GetDeviceHistoryWithPageAndPage(ctx context.Context, in *pb.Get
 		}
 
 		if ts, ok := mp["ts"]; ok && ts != "" {
-			// 解析时间字符串
-			t, err := time.Parse(layout, fmt.Sprintf("%s", ts))
+			t, err := timeutil.Parse(ts)
 			if err != nil {
-				log.Println("Error parsing time:", err)
+				logger.FromContext(ctx).Error("failed to parse time", "error", err)
 				return nil, err
 			}
-			m["ts"] = t.UnixNano() / int64(time.Millisecond)
+			m["ts"] = tf.Format(t)
 		}
 
 		m["device_id"] = in.GetDeviceId()
@@ -353,10 +373,10 @@ func (s *server) GetDeviceHistoryWithPageAndPage(ctx context.Context, in *pb.Get
 
 	dataJson, err := json.Marshal(retMapList)
 	if err != nil {
-		log.Printf("Failed to marshal dataMap: %v", err)
+		logger.FromContext(ctx).Error("failed to marshal dataMap", "error", err)
 		return &pb.GetDeviceHistoryWithPageAndPageReply{Status: 0, Message: "Failed to marshal dataMap", Data: ""}, nil
 	}
 
-	log.Println("dataJson: ", string(dataJson))
+	logger.FromContext(ctx).Debug("response", "data", string(dataJson))
 	return &pb.GetDeviceHistoryWithPageAndPageReply{Status: 1, Message: "", Data: string(dataJson)}, nil
 }
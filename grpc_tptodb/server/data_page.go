@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageRecords = 20
+	maxPageRecords     = 1000
+)
+
+// 分页游标：数据按ts倒序、k正序排列，(ts, k)唯一确定一行
+type historyCursor struct {
+	Ts       int64  `json:"t"` // 微秒
+	K        string `json:"k"`
+	Backward bool   `json:"b,omitempty"` // 向前翻页(更新的数据)
+	Scope    string `json:"s"`           // 查询条件的摘要，游标只能用于生成它的查询
+}
+
+// 设备、key和时间范围的摘要，key顺序不影响结果
+func cursorScope(deviceId string, keys []string, startTime, endTime int64) string {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%d\x00%d", deviceId, strings.Join(sorted, "\x00"), startTime, endTime)))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func (c historyCursor) encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// 解析游标并校验它属于当前查询
+func decodeHistoryCursor(s, scope string) (historyCursor, error) {
+	var c historyCursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("invalid cursor")
+	}
+	if c.Scope != scope {
+		return c, fmt.Errorf("cursor does not match device_id, keys or time range of the request")
+	}
+	return c, nil
+}
+
+// 按游标分页查询历史数据
+func historyPageByCursor(ctx context.Context, in *pb.GetDeviceHistoryWithPageAndPageRequest) (*pb.GetDeviceHistoryWithPageAndPageReply, error) {
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+	keys := in.GetKeys()
+	if len(keys) == 0 {
+		keys = []string{in.GetKey()}
+	}
+	size := int(in.GetPageRecords())
+	if size <= 0 {
+		size = defaultPageRecords
+	}
+	if size > maxPageRecords {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("page_records must not exceed %d", maxPageRecords))
+	}
+	// 游标分页不支持页码，翻页使用next_cursor/prev_cursor
+	if in.GetPage() > 1 {
+		return nil, status.Error(codes.InvalidArgument, "page is not supported, use cursor to fetch further pages")
+	}
+
+	scope := cursorScope(in.GetDeviceId(), keys, in.GetStartTime(), in.GetEndTime())
+	var cursor *historyCursor
+	if in.GetCursor() != "" {
+		c, err := decodeHistoryCursor(in.GetCursor(), scope)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		cursor = &c
+	}
+
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	if err := checkTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	where := func(finder *zorm.Finder) {
+		finder.Append("WHERE device_id = ? AND k in (?) AND ts >= ? AND ts <= ?", in.GetDeviceId(), keys, startTime, endTime)
+	}
+
+	// 多取一条判断是否还有数据
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s", db.DBName, db.SuperTableTv))
+	where(finder)
+	backward := cursor != nil && cursor.Backward
+	if cursor != nil {
+		ts := time.UnixMicro(cursor.Ts)
+		if backward {
+			finder.Append("AND (ts > ? OR (ts = ? AND k < ?))", ts, ts, cursor.K)
+		} else {
+			finder.Append("AND (ts < ? OR (ts = ? AND k > ?))", ts, ts, cursor.K)
+		}
+	}
+	if backward {
+		finder.Append(fmt.Sprintf("ORDER BY ts ASC,k DESC LIMIT %d", size+1))
+	} else {
+		finder.Append(fmt.Sprintf("ORDER BY ts DESC,k ASC LIMIT %d", size+1))
+	}
+
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query history page", "error", err)
+		return &pb.GetDeviceHistoryWithPageAndPageReply{Status: 0, Message: "Failed to QueryMap", Data: ""}, nil
+	}
+	hasMore := len(dataMap) > size
+	if hasMore {
+		dataMap = dataMap[:size]
+	}
+	if backward {
+		for i, j := 0, len(dataMap)-1; i < j; i, j = i+1, j-1 {
+			dataMap[i], dataMap[j] = dataMap[j], dataMap[i]
+		}
+	}
+
+	reply := &pb.GetDeviceHistoryWithPageAndPageReply{Status: 1}
+	var retMapList = make([]map[string]interface{}, 0, len(dataMap))
+	var first, last historyCursor
+	for i, mp := range dataMap {
+		t, err := timeutil.Parse(mp["ts"])
+		if err != nil {
+			return nil, err
+		}
+		c := historyCursor{Ts: t.UnixMicro(), K: fmt.Sprintf("%v", mp["k"]), Scope: scope}
+		if i == 0 {
+			first = c
+		}
+		last = c
+
+		m := historyRow(mp)
+		m["ts"] = tf.Format(t)
+		m["device_id"] = in.GetDeviceId()
+		retMapList = append(retMapList, m)
+	}
+
+	// 向后翻页或第一页：多出数据时有下一页，有游标时有上一页
+	// 向前翻页：总有下一页，多出数据时有上一页
+	if len(dataMap) > 0 {
+		if hasMore || backward {
+			reply.NextCursor = last.encode()
+		}
+		if (cursor != nil && !backward) || (backward && hasMore) {
+			first.Backward = true
+			reply.PrevCursor = first.encode()
+		}
+	}
+
+	if in.GetWithTotal() {
+		finder := zorm.NewFinder()
+		finder.Append(fmt.Sprintf("SELECT COUNT(*) AS total FROM %s.%s", db.DBName, db.SuperTableTv))
+		where(finder)
+		totalMap, err := db.QueryMap(ctx, finder, nil)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to count history", "error", err)
+			return &pb.GetDeviceHistoryWithPageAndPageReply{Status: 0, Message: "Failed to QueryMap", Data: ""}, nil
+		}
+		if len(totalMap) > 0 {
+			total, _ := numberValue(totalMap[0]["total"])
+			reply.Total = int64(total)
+		}
+	}
+
+	dataJson, err := json.Marshal(retMapList)
+	if err != nil {
+		return &pb.GetDeviceHistoryWithPageAndPageReply{Status: 0, Message: "Failed to marshal dataMap", Data: ""}, nil
+	}
+	logger.FromContext(ctx).Debug("response", "data", string(dataJson))
+	reply.Data = string(dataJson)
+	return reply, nil
+}
+
+// 历史数据行，去掉默认值；ts由调用方格式化
+func historyRow(mp map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	if string_v, ok := mp["string_v"]; ok {
+		if fmt.Sprintf("%v", string_v) != db.StringDefault {
+			m["string_v"] = string_v
+		}
+	}
+	if number_v, ok := mp["number_v"]; ok {
+		if v, ok := number_v.(float64); ok && v != db.NumberDefault {
+			m["number_v"] = v
+		}
+	}
+	if bool_v, ok := mp["bool_v"]; ok {
+		if v, ok := bool_v.(int); ok && v != db.BoolDefault {
+			m["bool_v"] = v
+		}
+	}
+	if k, ok := mp["k"]; ok {
+		m["key"] = k
+	}
+	return m
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestHistoryCursorRoundTrip(t *testing.T) {
+	scope := cursorScope("d1", []string{"temp", "hum"}, 1000, 2000)
+	c := historyCursor{Ts: 1697684400000000, K: "temp", Backward: true, Scope: scope}
+	got, err := decodeHistoryCursor(c.encode(), scope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != c {
+		t.Fatalf("got %+v, want %+v", got, c)
+	}
+}
+
+func TestHistoryCursorScope(t *testing.T) {
+	scope := cursorScope("d1", []string{"temp", "hum"}, 1000, 2000)
+	cursor := historyCursor{Ts: 1, K: "temp", Scope: scope}.encode()
+
+	tests := []struct {
+		name    string
+		scope   string
+		wantErr bool
+	}{
+		{name: "same query", scope: scope},
+		{name: "keys in other order", scope: cursorScope("d1", []string{"hum", "temp"}, 1000, 2000)},
+		{name: "other device", scope: cursorScope("d2", []string{"temp", "hum"}, 1000, 2000), wantErr: true},
+		{name: "other keys", scope: cursorScope("d1", []string{"temp"}, 1000, 2000), wantErr: true},
+		{name: "other start", scope: cursorScope("d1", []string{"temp", "hum"}, 999, 2000), wantErr: true},
+		{name: "other end", scope: cursorScope("d1", []string{"temp", "hum"}, 1000, 2001), wantErr: true},
+		// 分隔符保证拼接不同的参数不会得到相同摘要
+		{name: "ambiguous concatenation", scope: cursorScope("d1", []string{"temphum"}, 1000, 2000), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeHistoryCursor(cursor, tt.scope)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeHistoryCursorTampered(t *testing.T) {
+	scope := cursorScope("d1", []string{"temp"}, 1000, 2000)
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "not json", cursor: base64.RawURLEncoding.EncodeToString([]byte("not json"))},
+		{name: "wrong field type", cursor: base64.RawURLEncoding.EncodeToString([]byte(`{"t":"x","k":"temp","s":"` + scope + `"}`))},
+		{name: "missing scope", cursor: historyCursor{Ts: 1, K: "temp"}.encode()},
+		{name: "edited scope", cursor: historyCursor{Ts: 1, K: "temp", Scope: scope + "x"}.encode()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeHistoryCursor(tt.cursor, scope); err == nil {
+				t.Fatal("tampered cursor accepted")
+			}
+		})
+	}
+}
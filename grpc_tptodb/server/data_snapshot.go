@@ -0,0 +1,129 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 单次快照查询最多的设备数
+const maxSnapshotDevices = 1000
+
+// 设备在某一时刻的各key取值：每个key取at及之前的最后一行
+func (s *server) GetDeviceSnapshot(ctx context.Context, in *pb.GetDeviceSnapshotRequest) (*pb.GetDeviceSnapshotReply, error) {
+	deviceIds := make([]string, 0, len(in.GetDeviceId()))
+	seen := make(map[string]bool)
+	for _, id := range in.GetDeviceId() {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			deviceIds = append(deviceIds, id)
+		}
+	}
+	if len(deviceIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "device_id is required")
+	}
+	if len(deviceIds) > maxSnapshotDevices {
+		return nil, status.Errorf(codes.InvalidArgument, "too many devices, at most %d are allowed", maxSnapshotDevices)
+	}
+	if in.GetAt() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "at is required")
+	}
+	if in.GetMaxStaleness() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "max_staleness must not be negative")
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, k := range in.GetKeys() {
+		if k != "" {
+			keys = append(keys, k)
+		}
+	}
+
+	at := timeutil.FromMillis(in.GetAt())
+	lookback := snapshotLookback(in.GetMaxStaleness(), maxTimeSpan())
+
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT device_id,k,LAST_ROW(ts) AS ts,LAST_ROW(bool_v) AS bool_v,LAST_ROW(number_v) AS number_v,LAST_ROW(string_v) AS string_v FROM %s.%s WHERE device_id in (?) AND ts <= ?",
+		db.DBName, db.SuperTableTv), deviceIds, at)
+	if lookback > 0 {
+		finder.Append("AND ts >= ?", at.Add(-lookback))
+	}
+	if len(keys) > 0 {
+		finder.Append("AND k in (?)", keys)
+	}
+	finder.Append("PARTITION BY device_id,k")
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query snapshot", "devices", len(deviceIds), "error", err)
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	data, err := snapshotData(deviceIds, dataMap, at, tf)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal snapshot", "error", err)
+		return &pb.GetDeviceSnapshotReply{Status: 1, Message: err.Error(), Data: string("{}")}, nil
+	}
+	logger.FromContext(ctx).Debug("GetDeviceSnapshot", "devices", len(deviceIds), "at", at, "rows", len(dataMap))
+	return &pb.GetDeviceSnapshotReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+}
+
+// 回溯范围不超过max_staleness(毫秒)和query.max_time_span，均未限制时返回0
+func snapshotLookback(maxStaleness int64, span time.Duration) time.Duration {
+	lookback := time.Duration(maxStaleness) * time.Millisecond
+	if span > 0 && (lookback == 0 || lookback > span) {
+		lookback = span
+	}
+	return lookback
+}
+
+// 按设备整理快照，每个请求的设备都有一项，值按key排序，占位值行跳过
+func snapshotData(deviceIds []string, dataMap []map[string]interface{}, at time.Time, tf *timeutil.Formatter) (map[string][]map[string]interface{}, error) {
+	data := make(map[string][]map[string]interface{}, len(deviceIds))
+	for _, deviceId := range deviceIds {
+		data[deviceId] = make([]map[string]interface{}, 0)
+	}
+	for _, mp := range dataMap {
+		value, ok := typedValue(mp)
+		if !ok {
+			continue
+		}
+		ts, err := timeutil.Parse(mp["ts"])
+		if err != nil {
+			return nil, err
+		}
+		deviceId := fmt.Sprintf("%v", mp["device_id"])
+		data[deviceId] = append(data[deviceId], map[string]interface{}{
+			"key":   mp["k"],
+			"value": value,
+			"ts":    tf.Format(ts),
+			"age":   at.Sub(ts).Milliseconds(),
+		})
+	}
+	for _, values := range data {
+		sort.Slice(values, func(i, j int) bool { return fmt.Sprintf("%v", values[i]["key"]) < fmt.Sprintf("%v", values[j]["key"]) })
+	}
+	return data, nil
+}
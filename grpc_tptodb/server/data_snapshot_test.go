@@ -0,0 +1,67 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	"thingspanel-TDengine/timeutil"
+)
+
+func TestSnapshotLookback(t *testing.T) {
+	tests := []struct {
+		name         string
+		maxStaleness int64
+		span         time.Duration
+		want         time.Duration
+	}{
+		{name: "unlimited", want: 0},
+		{name: "staleness only", maxStaleness: 60000, want: time.Minute},
+		{name: "span only", span: 24 * time.Hour, want: 24 * time.Hour},
+		{name: "staleness within span", maxStaleness: 60000, span: time.Hour, want: time.Minute},
+		// 超过max_time_span时按max_time_span回溯，不返回错误
+		{name: "staleness capped by span", maxStaleness: 7200000, span: time.Hour, want: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snapshotLookback(tt.maxStaleness, tt.span); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnapshotData(t *testing.T) {
+	tf, err := timeutil.New("UTC", "ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.UnixMilli(1697767382000)
+	row := func(deviceId, k string, ts time.Time, b int, n float64, s string) map[string]interface{} {
+		return map[string]interface{}{"device_id": deviceId, "k": k, "ts": ts, "bool_v": b, "number_v": n, "string_v": s}
+	}
+	dataMap := []map[string]interface{}{
+		row("device-1", "temp", at.Add(-2*time.Second), db.BoolDefault, 21.5, db.StringDefault),
+		row("device-1", "running", at.Add(-382*time.Second), 1, db.NumberDefault, db.StringDefault),
+		row("device-1", "mode", at, db.BoolDefault, db.NumberDefault, "auto"),
+		// 占位值行不返回
+		row("device-1", "empty", at, db.BoolDefault, db.NumberDefault, db.StringDefault),
+	}
+	got, err := snapshotData([]string{"device-1", "device-2"}, dataMap, at, tf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]map[string]interface{}{
+		"device-1": {
+			{"key": "mode", "value": "auto", "ts": int64(1697767382000), "age": int64(0)},
+			{"key": "running", "value": true, "ts": int64(1697767000000), "age": int64(382000)},
+			{"key": "temp", "value": 21.5, "ts": int64(1697767380000), "age": int64(2000)},
+		},
+		// 没有数据的设备返回空列表
+		"device-2": {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
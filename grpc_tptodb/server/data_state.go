@@ -0,0 +1,269 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 状态统计支持的值类型及对应的列和占位值
+var stateColumns = map[string]struct {
+	column string
+	empty  string
+}{
+	"bool":   {column: "bool_v", empty: fmt.Sprintf("%d", db.BoolDefault)},
+	"string": {column: "string_v", empty: fmt.Sprintf("'%s'", db.StringDefault)},
+}
+
+// 一段连续相同状态，End为下一个状态的开始时间
+type stateInterval struct {
+	State interface{}
+	Start time.Time
+	End   time.Time
+}
+
+// 单个状态的累计时长(毫秒)和进入次数
+type stateStat struct {
+	State    interface{} `json:"state"`
+	Duration int64       `json:"duration"`
+	Count    int64       `json:"count"`
+}
+
+// bool、字符串key的状态时长和切换次数统计
+func (s *server) GetDeviceStateStats(ctx context.Context, in *pb.GetDeviceStateStatsRequest) (*pb.GetDeviceStateStatsReply, error) {
+	deviceId := in.GetDeviceId()
+	key := in.GetKey()
+	if deviceId == "" || key == "" {
+		return nil, status.Error(codes.InvalidArgument, "device_id and key are required")
+	}
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	if err := checkTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+	var calendar *calendarWindow
+	if in.GetBucket() != "" {
+		w, err := parseCalendarWindow(in.GetBucket())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		calendar = &w
+	}
+
+	valueType := in.GetValueType()
+	if valueType == "" {
+		if valueType, err = stateValueType(ctx, deviceId, key); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := stateColumns[valueType]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported value_type %q, expected bool or string", valueType)
+	}
+
+	// 最后一个状态持续到查询结束时间，结束时间在未来时截止到当前时间
+	until := endTime
+	if now := time.Now(); until.After(now) {
+		until = now
+	}
+	intervals, err := queryStateIntervals(ctx, deviceId, key, valueType, startTime, endTime, until)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query state intervals", "key", key, "error", err)
+		return nil, err
+	}
+
+	intervalList := make([]map[string]interface{}, len(intervals))
+	for i, v := range intervals {
+		intervalList[i] = map[string]interface{}{
+			"state":    v.State,
+			"start":    tf.Format(v.Start),
+			"end":      tf.Format(v.End),
+			"duration": v.End.Sub(v.Start).Milliseconds(),
+		}
+	}
+	transitions := 0
+	if len(intervals) > 1 {
+		transitions = len(intervals) - 1
+	}
+	data := map[string]interface{}{
+		"states":      stateStats(intervals),
+		"transitions": transitions,
+		"intervals":   intervalList,
+	}
+
+	// 按日历窗口切分状态区间，跨窗口的区间在每个窗口中各计一次
+	if calendar != nil {
+		bounds, err := calendar.boundaries(startTime, endTime, tf.Location())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		buckets := make([]map[string]interface{}, 0, len(bounds)-1)
+		for i := 0; i < len(bounds)-1; i++ {
+			var clipped []stateInterval
+			for _, v := range intervals {
+				if v.End.After(bounds[i]) && v.Start.Before(bounds[i+1]) {
+					c := v
+					if c.Start.Before(bounds[i]) {
+						c.Start = bounds[i]
+					}
+					if c.End.After(bounds[i+1]) {
+						c.End = bounds[i+1]
+					}
+					clipped = append(clipped, c)
+				}
+			}
+			buckets = append(buckets, map[string]interface{}{
+				"x":      tf.Format(bounds[i]),
+				"x2":     tf.Format(bounds[i+1]),
+				"states": stateStats(clipped),
+			})
+		}
+		data["buckets"] = buckets
+	}
+
+	jsonStr, err := json.Marshal(data)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal state stats", "error", err)
+		return &pb.GetDeviceStateStatsReply{Status: 1, Message: err.Error(), Data: string("{}")}, nil
+	}
+	logger.FromContext(ctx).Debug("GetDeviceStateStats", "key", key, "intervals", len(intervals))
+	return &pb.GetDeviceStateStatsReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+}
+
+// 按key的最新值判断值类型
+func stateValueType(ctx context.Context, deviceId, key string) (string, error) {
+	latest, err := db.GetLatest(ctx, []string{deviceId}, []string{key})
+	if err != nil {
+		return "", err
+	}
+	for _, v := range latest[deviceId] {
+		if v.Key != key {
+			continue
+		}
+		if v.BoolV != db.BoolDefault {
+			return "bool", nil
+		}
+		if v.StringV != db.StringDefault {
+			return "string", nil
+		}
+		return "", status.Errorf(codes.InvalidArgument, "key %s is not a bool or string key", key)
+	}
+	return "", status.Errorf(codes.NotFound, "key %s has no data", key)
+}
+
+// 用STATE_WINDOW查询按时间排序的状态区间，until为最后一个状态的结束时间
+// start之前的最后一个状态作为查询开始时的状态
+func queryStateIntervals(ctx context.Context, deviceId, key, valueType string, start, end, until time.Time) ([]stateInterval, error) {
+	col := stateColumns[valueType]
+	finder := zorm.NewFinder()
+	// 超级表上的STATE_WINDOW需要按子表分区，设备只在一个子表中
+	finder.Append(fmt.Sprintf("SELECT _wstart AS ws,FIRST(%s) AS state FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? AND %s <> %s PARTITION BY tbname STATE_WINDOW(%s)",
+		col.column, db.DBName, db.SuperTableTv, col.column, col.empty, col.column), deviceId, key, start, end)
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	intervals := make([]stateInterval, 0, len(dataMap)+1)
+	for _, v := range dataMap {
+		ws, err := timeutil.Parse(v["ws"])
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, stateInterval{State: stateValue(valueType, v["state"]), Start: ws})
+	}
+
+	finder = zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT LAST_ROW(%s) AS state FROM %s.%s WHERE device_id = ? AND k = ? AND ts < ? AND %s <> %s",
+		col.column, db.DBName, db.SuperTableTv, col.column, col.empty), deviceId, key, start)
+	finder.Append(rowLimit())
+	seedMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(seedMap)); err != nil {
+		return nil, err
+	}
+	var seed interface{}
+	if len(seedMap) > 0 && seedMap[0]["state"] != nil {
+		seed = stateValue(valueType, seedMap[0]["state"])
+	}
+	return buildStateIntervals(seed, intervals, start, until), nil
+}
+
+// bool状态统一为0/1
+func stateValue(valueType string, state interface{}) interface{} {
+	if valueType != "bool" {
+		return state
+	}
+	if b, ok := state.(bool); ok {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	if f, ok := numberValue(state); ok {
+		return int(f)
+	}
+	return state
+}
+
+// 按开始时间排序并补全结束时间；seed不为空时为start时刻的状态，从start开始计时
+func buildStateIntervals(seed interface{}, intervals []stateInterval, start, until time.Time) []stateInterval {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].Start.Before(intervals[j].Start) })
+	if seed != nil {
+		switch {
+		case len(intervals) > 0 && fmt.Sprintf("%v", intervals[0].State) == fmt.Sprintf("%v", seed):
+			// 范围内第一个状态与之前相同，是同一段状态的延续
+			intervals[0].Start = start
+		case len(intervals) == 0 || intervals[0].Start.After(start):
+			intervals = append([]stateInterval{{State: seed, Start: start}}, intervals...)
+		}
+	}
+	for i := range intervals {
+		if i+1 < len(intervals) {
+			intervals[i].End = intervals[i+1].Start
+		} else if until.After(intervals[i].Start) {
+			intervals[i].End = until
+		} else {
+			intervals[i].End = intervals[i].Start
+		}
+	}
+	return intervals
+}
+
+// 汇总各状态的时长和次数，按首次出现顺序返回
+func stateStats(intervals []stateInterval) []stateStat {
+	stats := make([]stateStat, 0)
+	index := make(map[string]int)
+	for _, v := range intervals {
+		k := fmt.Sprintf("%v", v.State)
+		i, ok := index[k]
+		if !ok {
+			i = len(stats)
+			index[k] = i
+			stats = append(stats, stateStat{State: v.State})
+		}
+		stats[i].Duration += v.End.Sub(v.Start).Milliseconds()
+		stats[i].Count++
+	}
+	return stats
+}
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildStateIntervals(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	start, until := at(0), at(60)
+
+	tests := []struct {
+		name      string
+		seed      interface{}
+		intervals []stateInterval
+		want      []stateInterval
+	}{
+		{
+			name:      "no seed starts at first change",
+			intervals: []stateInterval{{State: 1, Start: at(10)}, {State: 0, Start: at(30)}},
+			want:      []stateInterval{{State: 1, Start: at(10), End: at(30)}, {State: 0, Start: at(30), End: until}},
+		},
+		{
+			name:      "seed fills the gap before the first change",
+			seed:      0,
+			intervals: []stateInterval{{State: 1, Start: at(10)}},
+			want:      []stateInterval{{State: 0, Start: start, End: at(10)}, {State: 1, Start: at(10), End: until}},
+		},
+		{
+			name:      "seed equal to first state extends it",
+			seed:      1,
+			intervals: []stateInterval{{State: 1, Start: at(10)}, {State: 0, Start: at(30)}},
+			want:      []stateInterval{{State: 1, Start: start, End: at(30)}, {State: 0, Start: at(30), End: until}},
+		},
+		{
+			name: "seed without data in range lasts until end",
+			seed: "running",
+			want: []stateInterval{{State: "running", Start: start, End: until}},
+		},
+		{
+			name:      "change exactly at start replaces seed",
+			seed:      0,
+			intervals: []stateInterval{{State: 1, Start: start}},
+			want:      []stateInterval{{State: 1, Start: start, End: until}},
+		},
+		{
+			name:      "unsorted windows",
+			intervals: []stateInterval{{State: 0, Start: at(30)}, {State: 1, Start: at(10)}},
+			want:      []stateInterval{{State: 1, Start: at(10), End: at(30)}, {State: 0, Start: at(30), End: until}},
+		},
+		{
+			name: "no seed and no data",
+			want: []stateInterval{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildStateIntervals(tt.seed, tt.intervals, start, until)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildStateIntervalsUntilBeforeStart(t *testing.T) {
+	// 最后一个状态开始于until之后(结束时间在未来)时时长为0
+	start := time.Unix(1700000000, 0)
+	got := buildStateIntervals(nil, []stateInterval{{State: 1, Start: start.Add(time.Hour)}}, start, start)
+	if !got[0].End.Equal(got[0].Start) {
+		t.Fatalf("end = %v, want %v", got[0].End, got[0].Start)
+	}
+}
+
+func TestStateValue(t *testing.T) {
+	tests := []struct {
+		valueType string
+		in        interface{}
+		want      interface{}
+	}{
+		{"bool", true, 1},
+		{"bool", false, 0},
+		{"bool", int8(1), 1},
+		{"bool", float64(0), 0},
+		{"string", "on", "on"},
+	}
+	for _, tt := range tests {
+		if got := stateValue(tt.valueType, tt.in); got != tt.want {
+			t.Errorf("stateValue(%s, %v) = %v, want %v", tt.valueType, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStateStats(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	at := func(min int) time.Time { return base.Add(time.Duration(min) * time.Minute) }
+	intervals := []stateInterval{
+		{State: 1, Start: at(0), End: at(10)},
+		{State: 0, Start: at(10), End: at(15)},
+		{State: 1, Start: at(15), End: at(45)},
+	}
+	want := []stateStat{
+		{State: 1, Duration: 40 * 60 * 1000, Count: 2},
+		{State: 0, Duration: 5 * 60 * 1000, Count: 1},
+	}
+	if got := stateStats(intervals); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got := stateStats(nil); len(got) != 0 {
+		t.Fatalf("got %+v for no intervals", got)
+	}
+}
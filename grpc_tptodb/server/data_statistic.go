@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
 	db "thingspanel-TDengine/db"
 	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
 
 	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // 不聚合查询
@@ -18,80 +21,294 @@ func (s *server) GetDeviceKVDataWithNoAggregate(ctx context.Context, in *pb.GetD
 	var deviceId string = in.GetDeviceId()
 	var key string = in.GetKey()
 
-	startTime := time.Unix(0, in.GetStartTime()*int64(time.Millisecond))
-	endTime := time.Unix(0, in.GetEndTime()*int64(time.Millisecond))
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	if err := checkTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+
+	// 需要降采样且数据超过最大行数时，先在数据库中预聚合，不读取全部原始数据
+	maxPoints := int(in.GetMaxPoints())
+	if maxPoints > 0 {
+		if err := checkDownsample(in.GetDownsample()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		finder := zorm.NewFinder()
+		// other为布尔和字符串值的行数
+		finder.Append(fmt.Sprintf("SELECT COUNT(*) AS cnt,SUM(CASE WHEN number_v = %s AND (bool_v <> %d OR string_v <> '%s') THEN 1 ELSE 0 END) AS other FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ?",
+			formatNumber(db.NumberDefault), db.BoolDefault, db.StringDefault, db.DBName, db.SuperTableTv), deviceId, key, startTime, endTime)
+		countMap, err := db.QueryMap(ctx, finder, nil)
+		if err != nil {
+			return &pb.GetDeviceKVDataWithNoAggregateReply{Status: 1, Message: err.Error(), Data: string("{}")}, nil
+		}
+		var total, other float64
+		if len(countMap) > 0 {
+			total, _ = numberValue(countMap[0]["cnt"])
+			other, _ = numberValue(countMap[0]["other"])
+		}
+		if other > 0 {
+			return nil, errNonNumericDownsample
+		}
+		if int(total) > maxRows() {
+			// 每个窗口最多4个点，窗口数不超过max_points且点数不超过最大行数
+			buckets := maxPoints
+			if buckets > maxRows()/4 {
+				buckets = maxRows() / 4
+			}
+			points, original, err := queryM4Points(ctx, deviceId, key, startTime, endTime, buckets)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to query downsampled series", "key", key, "error", err)
+				return nil, err
+			}
+			sampled, _ := downsample(points, maxPoints, in.GetDownsample())
+			logger.FromContext(ctx).Debug("GetDeviceKVDataWithNoAggregate", "key", key, "rows", int(total), "aggregated", len(points), "sampled", len(sampled))
+			return seriesReply(sampled, original, tf)
+		}
+	}
 
 	finder := zorm.NewFinder()
 	query := "SELECT ts,k,bool_v,number_v,string_v,tenant_id FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? order by ts asc"
 	finder.Append(fmt.Sprintf(query, db.DBName, db.SuperTableTv), deviceId, key, startTime, endTime)
-	dataMap, err := zorm.QueryMap(ctx, finder, nil)
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
 	if err != nil {
 		return &pb.GetDeviceKVDataWithNoAggregateReply{Status: 1, Message: err.Error(), Data: string("{}")}, nil
 	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).Debug("GetDeviceKVDataWithNoAggregate", "key", key, "rows", len(dataMap))
+	reply := &pb.GetDeviceKVDataWithNoAggregateReply{Status: 1}
+
+	// 超过max_points时降采样，只支持数值属性
+	if maxPoints > 0 && len(dataMap) > maxPoints {
+		points, err := numericPoints(dataMap)
+		if err != nil {
+			return nil, err
+		}
+		sampled, _ := downsample(points, maxPoints, in.GetDownsample())
+		return seriesReply(sampled, int64(len(points)), tf)
+	}
 
-	log.Print("len: ", len(dataMap))
 	// 格式化
 	timeSeries := make([]map[string]interface{}, len(dataMap))
 	for i, v := range dataMap {
 		tmpMap := make(map[string]interface{})
 		ts, ok := v["ts"].(time.Time)
 		if ok {
-			tmpMap["x"] = ts.UnixMilli() // 处理时间戳成微秒
-			tmpMap["y"] = v["number_v"]  // 处理横轴
+			tmpMap["x"] = tf.Format(ts)
+			tmpMap["y"] = v["number_v"] // 处理横轴
 			timeSeries[i] = tmpMap
 		}
 	}
 	jsonStr, err := json.Marshal(timeSeries)
 	if err != nil {
-		log.Printf("Failed to marshal dataMap: %v", err)
+		logger.FromContext(ctx).Error("failed to marshal dataMap", "error", err)
 		return &pb.GetDeviceKVDataWithNoAggregateReply{Status: 1, Message: err.Error(), Data: string("{}")}, nil
 	}
 
-	return &pb.GetDeviceKVDataWithNoAggregateReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+	reply.Data = string(jsonStr)
+	return reply, nil
+}
+
+var errNonNumericDownsample = status.Error(codes.InvalidArgument, "max_points is only supported for numeric keys")
+
+// 取出数值点用于降采样，有布尔或字符串值时返回错误，占位值行跳过
+func numericPoints(dataMap []map[string]interface{}) ([]seriesPoint, error) {
+	points := make([]seriesPoint, 0, len(dataMap))
+	for _, v := range dataMap {
+		ts, ok := v["ts"].(time.Time)
+		if !ok {
+			continue
+		}
+		value, ok := typedValue(v)
+		if !ok {
+			continue
+		}
+		y, isNumber := value.(float64)
+		if !isNumber {
+			return nil, errNonNumericDownsample
+		}
+		points = append(points, seriesPoint{X: ts, Y: y})
+	}
+	return points, nil
+}
+
+// 降采样后的序列，点数少于原始点数时才标记为已降采样
+func seriesReply(points []seriesPoint, original int64, tf *timeutil.Formatter) (*pb.GetDeviceKVDataWithNoAggregateReply, error) {
+	timeSeries := make([]map[string]interface{}, len(points))
+	for i, p := range points {
+		timeSeries[i] = map[string]interface{}{"x": tf.Format(p.X), "y": p.Y}
+	}
+	jsonStr, err := json.Marshal(timeSeries)
+	if err != nil {
+		return &pb.GetDeviceKVDataWithNoAggregateReply{Status: 1, Message: err.Error(), Data: string("{}")}, nil
+	}
+	return &pb.GetDeviceKVDataWithNoAggregateReply{Status: 1, Data: string(jsonStr), Downsampled: int64(len(points)) < original, OriginalPoints: original}, nil
 }
 
 func (s *server) GetDeviceKVDataWithAggregate(ctx context.Context, in *pb.GetDeviceKVDataWithAggregateRequest) (*pb.GetDeviceKVDataWithAggregateReply, error) {
 	var deviceId string = in.GetDeviceId()
 	var key string = in.GetKey()
-	currentStartTime := in.GetStartTime()
-	currentEnd := in.GetEndTime()
 	window := in.GetAggregateWindow() //毫秒
-	log.Printf("currentStartTime: %v, window: %v\n", currentStartTime, window)
-
-	startTimeParsed := time.Unix(0, currentStartTime*int64(time.Millisecond))
-	endTimeParsed := time.Unix(0, currentEnd*int64(time.Millisecond))
+	offset := in.GetWindowOffset()    //毫秒
+	logger.FromContext(ctx).Debug("GetDeviceKVDataWithAggregate", "key", key, "start", in.GetStartTime(), "window", window, "offset", offset, "calendar_window", in.GetCalendarWindow())
+	startTimeParsed := timeutil.FromMillis(in.GetStartTime())
+	endTimeParsed := timeutil.FromMillis(in.GetEndTime())
+	if err := checkTimeRange(startTimeParsed, endTimeParsed); err != nil {
+		return nil, err
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
 
-	finder := zorm.NewFinder()
-	aggregateFunc := in.GetAggregateFunc()
-	queryStr := fmt.Sprintf("SELECT %s(number_v) AS v FROM things.ts_kv WHERE ts >= ? AND ts <= ? AND k = ? AND device_id = ? INTERVAL(%ds)",
-		aggregateFunc, window/1000)
-	finder.Append(queryStr, startTimeParsed, endTimeParsed, key, deviceId)
+	// 移动统计返回逐点序列
+	if in.GetMovingFunc() != "" {
+		sel, err := parseMovingFunc(in.GetMovingFunc())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		series, err := queryMovingSeries(ctx, deviceId, key, startTimeParsed, endTimeParsed, sel, tf)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to query moving series", "error", err)
+			return nil, err
+		}
+		jsonStr, err := json.Marshal(series)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.GetDeviceKVDataWithAggregateReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+	}
 
-	dataMap, err := zorm.QueryMap(ctx, finder, nil)
+	// aggregate_funcs为空时兼容单个aggregate_func，结果用y返回
+	funcs := in.GetAggregateFuncs()
+	multi := len(funcs) > 0
+	if !multi {
+		funcs = []string{in.GetAggregateFunc()}
+	}
+	selects, err := parseAggregateFuncs(funcs)
 	if err != nil {
-		log.Printf("Failed to SliceMap dataMap: %v", err)
-		return nil, err
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	// log.Printf("%+v\n", dataMap)
+	var buckets []aggregateBucket
+	if in.GetCalendarWindow() != "" {
+		// 日历窗口在Go中按时区计算边界，空窗口在Go中填充
+		if in.GetSliding() != 0 {
+			return nil, status.Error(codes.InvalidArgument, "sliding is not supported with calendar_window")
+		}
+		calendar, err := parseCalendarWindow(in.GetCalendarWindow())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		var present []bool
+		buckets, present, err = queryCalendarBuckets(ctx, deviceId, key, startTimeParsed, endTimeParsed, calendar, tf.Location(), selects)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to query calendar aggregate", "error", err)
+			return nil, err
+		}
+		if buckets, err = fillBuckets(buckets, present, in.GetFill(), in.GetFillValue(), len(selects)); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	} else {
+		if buckets, err = queryIntervalBuckets(ctx, in, startTimeParsed, endTimeParsed, selects); err != nil {
+			return nil, err
+		}
+	}
 
 	dataMapList := make([]map[string]interface{}, 0)
-	for _, v := range dataMap {
+	for _, b := range buckets {
 		tmpMap := make(map[string]interface{})
-		tmpMap["x"] = currentStartTime
-		tmpMap["x2"] = currentStartTime + window
-		tmpMap["y"] = v["v"]
+		tmpMap["x"] = tf.Format(b.Start)
+		tmpMap["x2"] = tf.Format(b.End)
+		if multi {
+			for i, sel := range selects {
+				tmpMap[sel.Name] = b.Values[i]
+			}
+		} else {
+			tmpMap["y"] = b.Values[0]
+		}
 		dataMapList = append(dataMapList, tmpMap)
-		currentStartTime = currentStartTime + window
 	}
 
-	log.Println("timeSeries len:", len(dataMapList))
+	logger.FromContext(ctx).Debug("timeSeries", "len", len(dataMapList))
 	jsonStr, err := json.Marshal(dataMapList)
 
 	if err != nil {
-		log.Printf("Failed to marshal dataMap: %v", err)
+		logger.FromContext(ctx).Error("failed to marshal dataMap", "error", err)
 		return &pb.GetDeviceKVDataWithAggregateReply{Status: 1, Message: "", Data: string("{}")}, nil
 	}
 
 	return &pb.GetDeviceKVDataWithAggregateReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
 }
+
+// 按固定时长窗口聚合，窗口时间取自_wstart/_wend，空窗口不会错位
+func queryIntervalBuckets(ctx context.Context, in *pb.GetDeviceKVDataWithAggregateRequest, start, end time.Time, selects []aggregateSelect) ([]aggregateBucket, error) {
+	window := in.GetAggregateWindow() //毫秒
+	offset := in.GetWindowOffset()    //毫秒
+	sliding := in.GetSliding()        //毫秒
+	if window <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "aggregate_window must be positive")
+	}
+	if offset < 0 || offset >= window {
+		return nil, status.Error(codes.InvalidArgument, "window_offset must be between 0 and aggregate_window")
+	}
+	var slidingClause string
+	if sliding != 0 {
+		// 重叠窗口，如5分钟窗口每30秒一个；偏移需小于步长
+		if sliding < 0 || sliding > window {
+			return nil, status.Error(codes.InvalidArgument, "sliding must be between 0 and aggregate_window")
+		}
+		if offset >= sliding {
+			return nil, status.Error(codes.InvalidArgument, "window_offset must be less than sliding")
+		}
+		slidingClause = fmt.Sprintf("SLIDING(%da)", sliding)
+	}
+	step := window
+	if sliding != 0 {
+		step = sliding
+	}
+	if err := checkWindows(start, end, step); err != nil {
+		return nil, err
+	}
+	fill, err := fillClause(in.GetFill(), in.GetFillValue(), len(selects))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	finder := zorm.NewFinder()
+	// 设备只在一个子表中，按tbname分区使percentile等单表函数可以在超级表上使用
+	queryStr := fmt.Sprintf("SELECT _wstart AS ws,_wend AS we,%s FROM %s.%s WHERE ts >= ? AND ts <= ? AND k = ? AND device_id = ? PARTITION BY tbname INTERVAL(%da,%da) %s %s",
+		aggregateColumns(selects), db.DBName, db.SuperTableTv, window, offset, slidingClause, fill)
+	finder.Append(queryStr, start, end, in.GetKey(), in.GetDeviceId())
+
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query aggregate", "error", err)
+		return nil, err
+	}
+
+	buckets := make([]aggregateBucket, 0, len(dataMap))
+	for _, v := range dataMap {
+		ws, err := timeutil.Parse(v["ws"])
+		if err != nil {
+			return nil, err
+		}
+		we, err := timeutil.Parse(v["we"])
+		if err != nil {
+			return nil, err
+		}
+		b := aggregateBucket{Start: ws, End: we, Values: make([]interface{}, len(selects))}
+		for i := range selects {
+			b.Values[i] = v[fmt.Sprintf("v%d", i)]
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, nil
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	"thingspanel-TDengine/timeutil"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNumericPoints(t *testing.T) {
+	ts := time.UnixMilli(1697767200000)
+	row := func(number float64, b int, s string) map[string]interface{} {
+		return map[string]interface{}{"ts": ts, "number_v": number, "bool_v": b, "string_v": s}
+	}
+	got, err := numericPoints([]map[string]interface{}{
+		row(1.5, db.BoolDefault, db.StringDefault),
+		row(db.NumberDefault, db.BoolDefault, db.StringDefault), // 占位值行跳过
+		row(-2, db.BoolDefault, db.StringDefault),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []seriesPoint{{X: ts, Y: 1.5}, {X: ts, Y: -2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	for name, r := range map[string]map[string]interface{}{
+		"bool":   row(db.NumberDefault, 1, db.StringDefault),
+		"string": row(db.NumberDefault, db.BoolDefault, "on"),
+	} {
+		if _, err := numericPoints([]map[string]interface{}{row(1, db.BoolDefault, db.StringDefault), r}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: err = %v, want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestSeriesReplyDownsampled(t *testing.T) {
+	tf, err := timeutil.New("UTC", "ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := []seriesPoint{{X: time.UnixMilli(0), Y: 1}, {X: time.UnixMilli(1000), Y: 2}}
+	tests := []struct {
+		original int64
+		want     bool
+	}{
+		{original: 2, want: false},
+		{original: 10, want: true},
+	}
+	for _, tt := range tests {
+		reply, err := seriesReply(points, tt.original, tf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reply.Downsampled != tt.want || reply.OriginalPoints != tt.original {
+			t.Errorf("original %d: downsampled = %v, want %v", tt.original, reply.Downsampled, tt.want)
+		}
+	}
+}
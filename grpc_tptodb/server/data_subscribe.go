@@ -0,0 +1,98 @@
+package server
+
+import (
+	"encoding/json"
+
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/pubsub"
+	"thingspanel-TDengine/timeutil"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 实时订阅设备遥测数据
+func (s *server) SubscribeTelemetry(in *pb.SubscribeTelemetryRequest, stream pb.ThingsPanel_SubscribeTelemetryServer) error {
+	deviceIds := in.GetDeviceId()
+	keys := in.GetKey()
+	if len(deviceIds) == 0 {
+		return status.Error(codes.InvalidArgument, "device_id is required")
+	}
+	ctx := stream.Context()
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return err
+	}
+
+	// 先订阅再查询快照，避免快照与实时数据之间丢点
+	sub := pubsub.Subscribe(deviceIds, keys, viper.GetInt("grpc.subscribe_buffer_size"))
+	defer pubsub.Unsubscribe(sub)
+	logger.FromContext(ctx).Info("SubscribeTelemetry", "devices", len(deviceIds), "keys", len(keys))
+
+	latest, err := queryLatestValues(ctx, deviceIds, keys, tf)
+	if err != nil {
+		return err
+	}
+	var snapshot = make([]map[string]interface{}, 0)
+	for _, deviceId := range deviceIds {
+		for _, m := range latest[deviceId] {
+			m["device_id"] = deviceId
+			snapshot = append(snapshot, m)
+		}
+	}
+	dataJson, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if err := stream.Send(&pb.SubscribeTelemetryReply{Status: 1, Message: "", Data: string(dataJson), Snapshot: true}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Evicted():
+			logger.FromContext(ctx).Warn("SubscribeTelemetry subscriber evicted, buffer full")
+			return status.Error(codes.ResourceExhausted, "subscriber too slow, buffer full")
+		case p := <-sub.C:
+			m := pointToMap(p, tf)
+			if m == nil {
+				continue
+			}
+			dataJson, err := json.Marshal([]map[string]interface{}{m})
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(&pb.SubscribeTelemetryReply{Status: 1, Message: "", Data: string(dataJson)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// 实时点转成与当前值查询一致的格式
+func pointToMap(p pubsub.Point, tf *timeutil.Formatter) map[string]interface{} {
+	m := map[string]interface{}{
+		"device_id": p.DeviceId,
+		"key":       p.Key,
+		"ts":        tf.Format(p.Ts),
+	}
+	switch v := p.Value.(type) {
+	case string:
+		m["string_v"] = v
+	case float64:
+		m["number_v"] = v
+	case bool:
+		bv := 0
+		if v {
+			bv = 1
+		}
+		m["bool_v"] = bv
+	default:
+		return nil
+	}
+	return m
+}
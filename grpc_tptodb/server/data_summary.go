@@ -0,0 +1,226 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 单次汇总最多的key数
+const maxSummaryKeys = 100
+
+// 多key统计汇总：数值key返回数值统计，字符串key返回不同值个数，bool key返回为真比例
+func (s *server) GetDeviceKVSummary(ctx context.Context, in *pb.GetDeviceKVSummaryRequest) (*pb.GetDeviceKVSummaryReply, error) {
+	deviceId := in.GetDeviceId()
+	keys := make([]string, 0, len(in.GetKeys()))
+	seen := make(map[string]bool)
+	for _, k := range in.GetKeys() {
+		if k != "" && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	if deviceId == "" || len(keys) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "device_id and keys are required")
+	}
+	if len(keys) > maxSummaryKeys {
+		return nil, status.Errorf(codes.InvalidArgument, "too many keys, at most %d are allowed", maxSummaryKeys)
+	}
+	startTime := timeutil.FromMillis(in.GetStartTime())
+	endTime := timeutil.FromMillis(in.GetEndTime())
+	if err := checkTimeRange(startTime, endTime); err != nil {
+		return nil, err
+	}
+	tf, err := requestFormatter(in)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同一key按各类型分别统计，取数据最多的类型
+	summaries := make(map[string]map[string]interface{}, len(keys))
+	numbers, err := numberSummary(ctx, deviceId, keys, startTime, endTime, tf)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to query number summary", "error", err)
+		return nil, err
+	}
+	pickSummaries(summaries, numbers)
+	for _, valueType := range []string{"string", "bool"} {
+		found, err := categorySummary(ctx, deviceId, keys, valueType, startTime, endTime, tf)
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to query summary", "value_type", valueType, "error", err)
+			return nil, err
+		}
+		pickSummaries(summaries, found)
+	}
+
+	dataList := make([]map[string]interface{}, len(keys))
+	for i, k := range keys {
+		if v, ok := summaries[k]; ok {
+			v["key"] = k
+			dataList[i] = v
+		} else {
+			dataList[i] = map[string]interface{}{"key": k, "count": 0}
+		}
+	}
+
+	jsonStr, err := json.Marshal(dataList)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to marshal summary", "error", err)
+		return &pb.GetDeviceKVSummaryReply{Status: 1, Message: err.Error(), Data: string("[]")}, nil
+	}
+	logger.FromContext(ctx).Debug("GetDeviceKVSummary", "keys", len(keys))
+	return &pb.GetDeviceKVSummaryReply{Status: 1, Message: "", Data: string(jsonStr)}, nil
+}
+
+// 数值key的统计，最值的时间用单个选择函数查询
+func numberSummary(ctx context.Context, deviceId string, keys []string, start, end time.Time, tf *timeutil.Formatter) (map[string]map[string]interface{}, error) {
+	where := fmt.Sprintf("FROM %s.%s WHERE device_id = ? AND k in (?) AND ts >= ? AND ts <= ? AND number_v <> %s PARTITION BY k",
+		db.DBName, db.SuperTableTv, formatNumber(db.NumberDefault))
+	finder := zorm.NewFinder()
+	finder.Append("SELECT k,COUNT(*) AS cnt,MIN(number_v) AS min_v,MAX(number_v) AS max_v,AVG(number_v) AS avg_v,STDDEV(number_v) AS stddev_v,"+
+		"FIRST(number_v) AS first_v,LAST(number_v) AS last_v,FIRST(ts) AS first_ts,LAST(ts) AS last_ts "+where, deviceId, keys, start, end)
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]map[string]interface{}, len(dataMap))
+	for _, v := range dataMap {
+		cnt, ok := numberValue(v["cnt"])
+		if !ok || cnt == 0 {
+			continue
+		}
+		m := map[string]interface{}{
+			"type":   "number",
+			"count":  int64(cnt),
+			"min":    v["min_v"],
+			"max":    v["max_v"],
+			"avg":    v["avg_v"],
+			"stddev": v["stddev_v"],
+			"first":  v["first_v"],
+			"last":   v["last_v"],
+		}
+		for _, name := range []string{"first_ts", "last_ts"} {
+			t, err := timeutil.Parse(v[name])
+			if err != nil {
+				return nil, err
+			}
+			m[name] = tf.Format(t)
+		}
+		ret[fmt.Sprintf("%v", v["k"])] = m
+	}
+
+	for name, fn := range map[string]string{"min_ts": "MIN", "max_ts": "MAX"} {
+		finder := zorm.NewFinder()
+		finder.Append(fmt.Sprintf("SELECT k,ts,%s(number_v) AS v ", fn)+where, deviceId, keys, start, end)
+		finder.Append(rowLimit())
+		dataMap, err := db.QueryMap(ctx, finder, nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkRows(len(dataMap)); err != nil {
+			return nil, err
+		}
+		for _, v := range dataMap {
+			m, ok := ret[fmt.Sprintf("%v", v["k"])]
+			if !ok {
+				continue
+			}
+			t, err := timeutil.Parse(v["ts"])
+			if err != nil {
+				return nil, err
+			}
+			m[name] = tf.Format(t)
+		}
+	}
+	return ret, nil
+}
+
+// 同一key有多种类型的统计时保留数据最多的类型
+func pickSummaries(summaries, found map[string]map[string]interface{}) {
+	for k, v := range found {
+		if cur, ok := summaries[k]; !ok || v["count"].(int64) > cur["count"].(int64) {
+			summaries[k] = v
+		}
+	}
+}
+
+// 字符串和bool key按key聚合，字符串的不同值个数用HYPERLOGLOG估算，bool统计为真的条数
+func categorySummary(ctx context.Context, deviceId string, keys []string, valueType string, start, end time.Time, tf *timeutil.Formatter) (map[string]map[string]interface{}, error) {
+	col := stateColumns[valueType]
+	extra := fmt.Sprintf("HYPERLOGLOG(%s)", col.column)
+	if valueType == "bool" {
+		extra = fmt.Sprintf("SUM(CASE WHEN %s <> 0 THEN 1 ELSE 0 END)", col.column)
+	}
+	finder := zorm.NewFinder()
+	finder.Append(fmt.Sprintf("SELECT k,COUNT(*) AS cnt,%s AS extra,FIRST(%s) AS first_v,LAST(%s) AS last_v,FIRST(ts) AS first_ts,LAST(ts) AS last_ts "+
+		"FROM %s.%s WHERE device_id = ? AND k in (?) AND ts >= ? AND ts <= ? AND %s <> %s PARTITION BY k",
+		extra, col.column, col.column, db.DBName, db.SuperTableTv, col.column, col.empty), deviceId, keys, start, end)
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]map[string]interface{}, len(dataMap))
+	for _, v := range dataMap {
+		m, err := categoryEntry(valueType, v, tf)
+		if err != nil {
+			return nil, err
+		}
+		if m != nil {
+			ret[fmt.Sprintf("%v", v["k"])] = m
+		}
+	}
+	return ret, nil
+}
+
+// 一个key的字符串或bool统计，无数据时返回nil
+func categoryEntry(valueType string, v map[string]interface{}, tf *timeutil.Formatter) (map[string]interface{}, error) {
+	cnt, ok := numberValue(v["cnt"])
+	if !ok || cnt == 0 {
+		return nil, nil
+	}
+	m := map[string]interface{}{
+		"type":  valueType,
+		"count": int64(cnt),
+		"first": v["first_v"],
+		"last":  v["last_v"],
+	}
+	for _, name := range []string{"first_ts", "last_ts"} {
+		t, err := timeutil.Parse(v[name])
+		if err != nil {
+			return nil, err
+		}
+		m[name] = tf.Format(t)
+	}
+	extra, _ := numberValue(v["extra"])
+	if valueType == "bool" {
+		for _, name := range []string{"first", "last"} {
+			f, _ := numberValue(m[name])
+			m[name] = int(f)
+		}
+		m["true_count"] = int64(extra)
+		m["true_ratio"] = extra / cnt
+	} else {
+		m["distinct"] = int64(extra)
+	}
+	return m, nil
+}
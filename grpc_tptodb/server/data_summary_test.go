@@ -0,0 +1,83 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"thingspanel-TDengine/timeutil"
+)
+
+func TestPickSummaries(t *testing.T) {
+	summaries := map[string]map[string]interface{}{}
+	pickSummaries(summaries, map[string]map[string]interface{}{
+		"temp": {"type": "number", "count": int64(10)},
+		"mode": {"type": "number", "count": int64(1)},
+	})
+	pickSummaries(summaries, map[string]map[string]interface{}{
+		"temp": {"type": "string", "count": int64(3)},
+		"mode": {"type": "string", "count": int64(5)},
+		"door": {"type": "string", "count": int64(2)},
+	})
+	// 数量相同时保留先统计的类型
+	pickSummaries(summaries, map[string]map[string]interface{}{
+		"door": {"type": "bool", "count": int64(2)},
+	})
+	want := map[string]string{"temp": "number", "mode": "string", "door": "string"}
+	for k, typ := range want {
+		if got := summaries[k]["type"]; got != typ {
+			t.Errorf("%s: type = %v, want %s", k, got, typ)
+		}
+	}
+}
+
+func TestCategoryEntry(t *testing.T) {
+	tf, err := timeutil.New("UTC", "ms")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, last := time.UnixMilli(1697680800000), time.UnixMilli(1697767200000)
+	tests := []struct {
+		name      string
+		valueType string
+		row       map[string]interface{}
+		want      map[string]interface{}
+	}{
+		{
+			name:      "string distinct",
+			valueType: "string",
+			row:       map[string]interface{}{"cnt": int64(12), "extra": int64(3), "first_v": "auto", "last_v": "manual", "first_ts": first, "last_ts": last},
+			want: map[string]interface{}{"type": "string", "count": int64(12), "distinct": int64(3), "first": "auto", "last": "manual",
+				"first_ts": int64(1697680800000), "last_ts": int64(1697767200000)},
+		},
+		{
+			name:      "bool ratio",
+			valueType: "bool",
+			row:       map[string]interface{}{"cnt": int64(40), "extra": int64(10), "first_v": int8(0), "last_v": int8(1), "first_ts": first, "last_ts": last},
+			want: map[string]interface{}{"type": "bool", "count": int64(40), "true_count": int64(10), "true_ratio": 0.25, "first": 0, "last": 1,
+				"first_ts": int64(1697680800000), "last_ts": int64(1697767200000)},
+		},
+		{
+			name:      "no data",
+			valueType: "bool",
+			row:       map[string]interface{}{"cnt": int64(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := categoryEntry(tt.valueType, tt.row, tf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.want == nil {
+				if got != nil {
+					t.Fatalf("got %v, want nil", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+)
+
+// 降采样的数据点
+type seriesPoint struct {
+	X time.Time
+	Y float64
+}
+
+// 降采样方法：lttb保留曲线形状，minmax保留每个像素区间的首末点和最大最小值(适合查看毛刺)
+func downsample(points []seriesPoint, maxPoints int, method string) ([]seriesPoint, error) {
+	if err := checkDownsample(method); err != nil {
+		return nil, err
+	}
+	if method == "minmax" {
+		return minMax(points, maxPoints), nil
+	}
+	return lttb(points, maxPoints), nil
+}
+
+func checkDownsample(method string) error {
+	switch method {
+	case "", "lttb", "minmax":
+		return nil
+	}
+	return fmt.Errorf("unsupported downsample method %q, expected lttb or minmax", method)
+}
+
+// Largest-Triangle-Three-Buckets
+// 首末点保留，中间点分成maxPoints-2个桶，每个桶选取与前一个选中点、下一个桶均值构成三角形面积最大的点
+// maxPoints小于3时只保留首点或首末点
+func lttb(points []seriesPoint, maxPoints int) []seriesPoint {
+	switch {
+	case maxPoints <= 0 || maxPoints >= len(points):
+		return points
+	case maxPoints == 1:
+		return points[:1]
+	case maxPoints == 2:
+		return []seriesPoint{points[0], points[len(points)-1]}
+	}
+
+	sampled := make([]seriesPoint, 0, maxPoints)
+	sampled = append(sampled, points[0])
+	every := float64(len(points)-2) / float64(maxPoints-2)
+	a := 0
+	for i := 0; i < maxPoints-2; i++ {
+		// 下一个桶的均值
+		nextStart := int(math.Floor(float64(i+1)*every)) + 1
+		nextEnd := int(math.Floor(float64(i+2)*every)) + 1
+		if nextEnd > len(points) {
+			nextEnd = len(points)
+		}
+		var avgX, avgY float64
+		for _, p := range points[nextStart:nextEnd] {
+			avgX += float64(p.X.UnixMicro())
+			avgY += p.Y
+		}
+		n := float64(nextEnd - nextStart)
+		avgX, avgY = avgX/n, avgY/n
+
+		// 当前桶中面积最大的点
+		start := int(math.Floor(float64(i)*every)) + 1
+		end := nextStart
+		ax, ay := float64(points[a].X.UnixMicro()), points[a].Y
+		maxArea, maxIndex := -1.0, start
+		for j := start; j < end; j++ {
+			area := math.Abs((ax-avgX)*(points[j].Y-ay) - (ax-float64(points[j].X.UnixMicro()))*(avgY-ay))
+			if area > maxArea {
+				maxArea, maxIndex = area, j
+			}
+		}
+		sampled = append(sampled, points[maxIndex])
+		a = maxIndex
+	}
+	return append(sampled, points[len(points)-1])
+}
+
+// 按时间均分为maxPoints/4个区间(M4)，每个区间保留首点、最小值、最大值和末点，按时间顺序输出
+// maxPoints小于4时不足一个区间，按lttb保留首末点
+func minMax(points []seriesPoint, maxPoints int) []seriesPoint {
+	buckets := maxPoints / 4
+	if maxPoints >= len(points) || buckets < 1 {
+		return lttb(points, maxPoints)
+	}
+
+	first, last := points[0].X, points[len(points)-1].X
+	width := last.Sub(first) / time.Duration(buckets)
+	if width <= 0 {
+		width = 1
+	}
+	// 最后一个点落在最后一个区间
+	bucketOf := func(t time.Time) int {
+		if n := int(t.Sub(first) / width); n < buckets {
+			return n
+		}
+		return buckets - 1
+	}
+	sampled := make([]seriesPoint, 0, maxPoints)
+	for i := 0; i < len(points); {
+		bucket := bucketOf(points[i].X)
+		minIndex, maxIndex := i, i
+		j := i
+		for ; j < len(points) && bucketOf(points[j].X) == bucket; j++ {
+			if points[j].Y < points[minIndex].Y {
+				minIndex = j
+			}
+			if points[j].Y > points[maxIndex].Y {
+				maxIndex = j
+			}
+		}
+		// 首点、最值点、末点去重后按下标(时间)排序
+		indexes := []int{i, minIndex, maxIndex, j - 1}
+		sort.Ints(indexes)
+		for k, idx := range indexes {
+			if k == 0 || idx != indexes[k-1] {
+				sampled = append(sampled, points[idx])
+			}
+		}
+		i = j
+	}
+	return sampled
+}
+
+// 数据点超过最大行数时先在数据库中按INTERVAL预聚合(M4)：每个窗口取首末点和最值点，再在Go中降采样
+// 返回按时间排序的点和参与聚合的原始数值点数
+func queryM4Points(ctx context.Context, deviceId, key string, start, end time.Time, buckets int) ([]seriesPoint, int64, error) {
+	if buckets < 1 {
+		buckets = 1
+	}
+	window := end.Sub(start).Milliseconds()/int64(buckets) + 1 // 毫秒
+	where := fmt.Sprintf("FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? AND number_v <> %s PARTITION BY tbname INTERVAL(%da)",
+		db.DBName, db.SuperTableTv, formatNumber(db.NumberDefault), window)
+
+	byTs := make(map[int64]seriesPoint)
+	add := func(ts, v interface{}) error {
+		t, err := timeutil.Parse(ts)
+		if err != nil {
+			return err
+		}
+		if y, ok := numberValue(v); ok {
+			byTs[t.UnixMicro()] = seriesPoint{X: t, Y: y}
+		}
+		return nil
+	}
+
+	finder := zorm.NewFinder()
+	finder.Append("SELECT COUNT(*) AS cnt,FIRST(ts) AS first_ts,FIRST(number_v) AS first_v,LAST(ts) AS last_ts,LAST(number_v) AS last_v "+where,
+		deviceId, key, start, end)
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	for _, v := range dataMap {
+		cnt, _ := numberValue(v["cnt"])
+		total += int64(cnt)
+		if err := add(v["first_ts"], v["first_v"]); err != nil {
+			return nil, 0, err
+		}
+		if err := add(v["last_ts"], v["last_v"]); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	// 最值的时间用单个选择函数查询
+	for _, fn := range []string{"MIN", "MAX"} {
+		finder := zorm.NewFinder()
+		finder.Append(fmt.Sprintf("SELECT ts,%s(number_v) AS v ", fn)+where, deviceId, key, start, end)
+		finder.Append(rowLimit())
+		dataMap, err := db.QueryMap(ctx, finder, nil)
+		if err != nil {
+			return nil, 0, err
+		}
+		if err := checkRows(len(dataMap)); err != nil {
+			return nil, 0, err
+		}
+		for _, v := range dataMap {
+			if err := add(v["ts"], v["v"]); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	points := make([]seriesPoint, 0, len(byTs))
+	for _, p := range byTs {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].X.Before(points[j].X) })
+	return points, total, nil
+}
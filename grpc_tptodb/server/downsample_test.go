@@ -0,0 +1,174 @@
+package server
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// n个点，每秒一个，值由f给出
+func makeSeries(n int, f func(i int) float64) []seriesPoint {
+	base := time.Unix(1700000000, 0)
+	points := make([]seriesPoint, n)
+	for i := range points {
+		points[i] = seriesPoint{X: base.Add(time.Duration(i) * time.Second), Y: f(i)}
+	}
+	return points
+}
+
+func sortedByTime(points []seriesPoint) bool {
+	for i := 1; i < len(points); i++ {
+		if !points[i-1].X.Before(points[i].X) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLttb(t *testing.T) {
+	sine := makeSeries(1000, func(i int) float64 { return math.Sin(float64(i) / 50) })
+	tests := []struct {
+		name      string
+		points    []seriesPoint
+		maxPoints int
+		wantLen   int
+	}{
+		{name: "fewer points than max", points: sine[:10], maxPoints: 20, wantLen: 10},
+		{name: "equal to max", points: sine[:10], maxPoints: 10, wantLen: 10},
+		{name: "downsampled", points: sine, maxPoints: 100, wantLen: 100},
+		{name: "three points", points: sine, maxPoints: 3, wantLen: 3},
+		{name: "two points keeps first and last", points: sine, maxPoints: 2, wantLen: 2},
+		{name: "one point keeps first", points: sine, maxPoints: 1, wantLen: 1},
+		{name: "zero disables", points: sine[:10], maxPoints: 0, wantLen: 10},
+		{name: "empty", points: nil, maxPoints: 5, wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := lttb(tt.points, tt.maxPoints)
+			if len(got) != tt.wantLen {
+				t.Fatalf("len = %d, want %d", len(got), tt.wantLen)
+			}
+			if !sortedByTime(got) {
+				t.Fatal("points not in time order")
+			}
+			if len(got) > 0 && got[0] != tt.points[0] {
+				t.Fatal("first point not kept")
+			}
+			if len(got) > 1 && got[len(got)-1] != tt.points[len(tt.points)-1] {
+				t.Fatal("last point not kept")
+			}
+		})
+	}
+}
+
+func TestLttbKeepsSpike(t *testing.T) {
+	points := makeSeries(1000, func(i int) float64 {
+		if i == 500 {
+			return 100
+		}
+		return 0
+	})
+	for _, p := range lttb(points, 50) {
+		if p.Y == 100 {
+			return
+		}
+	}
+	t.Fatal("spike lost")
+}
+
+func TestMinMax(t *testing.T) {
+	// 每10个点一个区间，最值都不在区间首末
+	shape := []float64{5, 7, 9, 6, 4, 1, 3, 2, 6, 5}
+	wave := makeSeries(100, func(i int) float64 { return shape[i%10] })
+	tests := []struct {
+		name      string
+		points    []seriesPoint
+		maxPoints int
+		wantLen   int
+	}{
+		{name: "fewer points than max", points: wave[:5], maxPoints: 10, wantLen: 5},
+		{name: "one bucket per ten points", points: wave, maxPoints: 40, wantLen: 40},
+		{name: "below one bucket falls back to lttb", points: wave, maxPoints: 3, wantLen: 3},
+		{name: "two points", points: wave, maxPoints: 2, wantLen: 2},
+		{name: "one point", points: wave, maxPoints: 1, wantLen: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := minMax(tt.points, tt.maxPoints)
+			if len(got) != tt.wantLen {
+				t.Fatalf("len = %d, want %d", len(got), tt.wantLen)
+			}
+			if len(got) > tt.maxPoints && tt.maxPoints < len(tt.points) {
+				t.Fatalf("len = %d exceeds max %d", len(got), tt.maxPoints)
+			}
+			if !sortedByTime(got) {
+				t.Fatal("points not in time order")
+			}
+			if got[0] != tt.points[0] {
+				t.Fatal("first point not kept")
+			}
+			if len(got) > 1 && got[len(got)-1] != tt.points[len(tt.points)-1] {
+				t.Fatal("last point not kept")
+			}
+		})
+	}
+}
+
+func TestMinMaxBucket(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []float64
+		maxPoints int
+		want      []float64
+	}{
+		{
+			// 一个区间：首点5、最大9、最小0、末点8，按时间顺序
+			name:      "one bucket",
+			values:    []float64{5, 9, 7, 1, 6, 2, 0, 8},
+			maxPoints: 4,
+			want:      []float64{5, 9, 0, 8},
+		},
+		{
+			// 两个区间各6个点，最小值在最大值之前时也按时间顺序输出
+			name:      "two buckets",
+			values:    []float64{5, 9, 7, 1, 6, 2, 3, 0, 8, 4, 4, 6},
+			maxPoints: 8,
+			want:      []float64{5, 9, 1, 2, 3, 0, 8, 6},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := makeSeries(len(tt.values), func(i int) float64 { return tt.values[i] })
+			got := minMax(points, tt.maxPoints)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i].Y != tt.want[i] {
+					t.Fatalf("point %d = %v, want %v", i, got[i].Y, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMinMaxConstant(t *testing.T) {
+	// 值不变时首点即最值点，每个区间只输出首末两个点
+	points := makeSeries(100, func(int) float64 { return 1 })
+	got := minMax(points, 20)
+	if len(got) != 10 {
+		t.Fatalf("len = %d, want 10", len(got))
+	}
+}
+
+func TestDownsampleMethod(t *testing.T) {
+	points := makeSeries(10, func(i int) float64 { return float64(i) })
+	for _, method := range []string{"", "lttb", "minmax"} {
+		if _, err := downsample(points, 5, method); err != nil {
+			t.Fatalf("method %q: %v", method, err)
+		}
+	}
+	if _, err := downsample(points, 5, "avg"); err == nil {
+		t.Fatal("unsupported method accepted")
+	}
+}
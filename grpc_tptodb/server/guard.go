@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	db "thingspanel-TDengine/db"
+
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 单次查询返回的最大行数(query.max_rows)
+func maxRows() int {
+	if n := viper.GetInt("query.max_rows"); n > 0 {
+		return n
+	}
+	return 100000
+}
+
+// 单次查询的最大时间跨度(query.max_time_span，单位秒)，为0时不限制
+func maxTimeSpan() time.Duration {
+	return viper.GetDuration("query.max_time_span") * time.Second
+}
+
+// 校验查询时间范围
+func checkTimeRange(start, end time.Time) error {
+	if start.After(end) {
+		return status.Error(codes.InvalidArgument, "start_time must not be after end_time")
+	}
+	if span := maxTimeSpan(); span > 0 && end.Sub(start) > span {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("time range must not exceed %s", span))
+	}
+	return nil
+}
+
+// 查询时多取一行用于判断是否超过最大行数
+func rowLimit() string {
+	return fmt.Sprintf("LIMIT %d", maxRows()+1)
+}
+
+// 结果超过最大行数时返回错误，而不是返回不完整的数据
+func checkRows(n int) error {
+	if n > maxRows() {
+		return status.Error(codes.ResourceExhausted, fmt.Sprintf("result exceeds %d rows, narrow the time range or use aggregation", maxRows()))
+	}
+	return nil
+}
+
+// 校验窗口数，step为窗口步长(毫秒)
+func checkWindows(start, end time.Time, step int64) error {
+	if step > 0 && end.Sub(start).Milliseconds()/step > int64(maxRows()) {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("too many windows, at most %d are allowed", maxRows()))
+	}
+	return nil
+}
+
+// 将数据库超时转换为DeadlineExceeded
+func guardUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return nil, status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return resp, err
+}
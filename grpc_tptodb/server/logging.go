@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"thingspanel-TDengine/logger"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// 生成带request_id、method、tenant_id、device_id字段的请求日志并写入上下文
+// request_id优先取metadata中的x-request-id，其次为trace id
+func requestLogger(ctx context.Context, fullMethod string, req interface{}) *slog.Logger {
+	var requestId string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if ids := md.Get("x-request-id"); len(ids) > 0 && ids[0] != "" {
+		requestId = ids[0]
+	} else if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		requestId = sc.TraceID().String()
+	} else {
+		requestId = uuid.New().String()
+	}
+
+	l := logger.Grpc.With("request_id", requestId, "method", fullMethod)
+	if tenant, ok := tenantFromContext(ctx); ok {
+		l = l.With("tenant_id", tenant)
+	}
+	return withDeviceId(l, req)
+}
+
+// 请求只涉及一个设备时加上device_id字段
+func withDeviceId(l *slog.Logger, req interface{}) *slog.Logger {
+	var deviceId string
+	switch r := req.(type) {
+	case interface{ GetDeviceId() string }:
+		deviceId = r.GetDeviceId()
+	case interface{ GetDeviceId() []string }:
+		if ids := r.GetDeviceId(); len(ids) == 1 {
+			deviceId = ids[0]
+		}
+	}
+	if deviceId == "" {
+		return l
+	}
+	return l.With("device_id", deviceId)
+}
+
+func logRequest(l *slog.Logger, start time.Time, err error) {
+	if err != nil {
+		l.Warn("request failed", "code", status.Code(err).String(), "duration", time.Since(start), "error", err)
+		return
+	}
+	l.Info("request", "duration", time.Since(start))
+}
+
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	l := requestLogger(ctx, info.FullMethod, req)
+	resp, err := handler(logger.NewContext(ctx, l), req)
+	logRequest(l, start, err)
+	return resp, err
+}
+
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	l := requestLogger(ss.Context(), info.FullMethod, nil)
+	stream := &loggingServerStream{ServerStream: ss, ctx: logger.NewContext(ss.Context(), l), logger: l}
+	err := handler(srv, stream)
+	logRequest(stream.logger, start, err)
+	return err
+}
+
+type loggingServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	logger   *slog.Logger
+	received bool
+}
+
+// 流式请求在收到第一条消息后才知道device_id
+func (s *loggingServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && !s.received {
+		s.received = true
+		s.logger = withDeviceId(s.logger, m)
+		s.ctx = logger.NewContext(s.ctx, s.logger)
+	}
+	return err
+}
+
+func (s *loggingServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	pb "thingspanel-TDengine/grpc_tptodb"
+)
+
+func TestWithDeviceId(t *testing.T) {
+	tests := []struct {
+		name string
+		req  interface{}
+		want interface{}
+	}{
+		{name: "single device", req: &pb.GetDeviceHistoryRequest{DeviceId: "d1"}, want: "d1"},
+		{name: "empty device", req: &pb.GetDeviceHistoryRequest{}, want: nil},
+		{name: "one of list", req: &pb.GetDeviceSnapshotRequest{DeviceId: []string{"d1"}}, want: "d1"},
+		{name: "many devices", req: &pb.GetDeviceSnapshotRequest{DeviceId: []string{"d1", "d2"}}, want: nil},
+		{name: "no request", req: nil, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			withDeviceId(slog.New(slog.NewJSONHandler(&buf, nil)), tt.req).Info("request")
+			var fields map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &fields); err != nil {
+				t.Fatal(err)
+			}
+			if got := fields["device_id"]; got != tt.want {
+				t.Fatalf("device_id = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
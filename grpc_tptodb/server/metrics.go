@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"thingspanel-TDengine/metrics"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// 记录请求耗时和错误数
+// 返回status为0的业务错误按code=app_error计数
+func metricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.RpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.RpcErrors.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+	} else if r, ok := resp.(interface{ GetStatus() int64 }); ok && r.GetStatus() == 0 {
+		metrics.RpcErrors.WithLabelValues(info.FullMethod, "app_error").Inc()
+	}
+	return resp, err
+}
+
+func metricsStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	metrics.RpcDuration.WithLabelValues(info.FullMethod).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.RpcErrors.WithLabelValues(info.FullMethod, status.Code(err).String()).Inc()
+	}
+	return err
+}
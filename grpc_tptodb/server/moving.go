@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	db "thingspanel-TDengine/db"
+	"thingspanel-TDengine/timeutil"
+
+	"gitee.com/chunanyong/zorm"
+)
+
+// 移动统计函数，返回逐点序列而不是窗口
+// mavg、csum由TDengine计算；mstddev在Go中按最近k个点计算
+type movingFunc struct {
+	expr  string // SQL表达式，%s为参数；为空时在Go中计算
+	param bool   // 参数为点数k
+}
+
+var movingFuncs = map[string]movingFunc{
+	"mavg":    {expr: "MAVG(number_v, %s)", param: true},
+	"csum":    {expr: "CSUM(number_v)"},
+	"mstddev": {param: true},
+}
+
+// 移动统计的最大点数k
+const maxMovingPoints = 1000
+
+type movingSelect struct {
+	name string
+	expr string
+	k    int
+}
+
+// 解析移动统计函数，如mavg(5)、csum、mstddev(10)
+func parseMovingFunc(s string) (movingSelect, error) {
+	m := aggregateFuncPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if m == nil {
+		return movingSelect{}, fmt.Errorf("invalid moving function %q", s)
+	}
+	name, param := m[1], m[2]
+	fn, ok := movingFuncs[name]
+	if !ok {
+		return movingSelect{}, fmt.Errorf("unsupported moving function %q", name)
+	}
+	if !fn.param {
+		if param != "" {
+			return movingSelect{}, fmt.Errorf("moving function %s takes no parameter", name)
+		}
+		return movingSelect{name: name, expr: fn.expr}, nil
+	}
+	k, err := strconv.Atoi(param)
+	if err != nil || k < 1 || k > maxMovingPoints {
+		return movingSelect{}, fmt.Errorf("moving function %s parameter must be an integer between 1 and %d", name, maxMovingPoints)
+	}
+	sel := movingSelect{name: name, k: k}
+	if fn.expr != "" {
+		sel.expr = fmt.Sprintf(fn.expr, strconv.Itoa(k))
+	}
+	return sel, nil
+}
+
+// 查询移动统计序列，返回[{x, y}]
+func queryMovingSeries(ctx context.Context, deviceId, key string, start, end time.Time, sel movingSelect, tf *timeutil.Formatter) ([]map[string]interface{}, error) {
+	// 排除同一key写入字符串和布尔值时的数值占位行
+	finder := zorm.NewFinder()
+	if sel.expr != "" {
+		// 超级表上的MAVG、CSUM需要按子表分区，设备只在一个子表中，结果按时间有序
+		finder.Append(fmt.Sprintf("SELECT _rowts AS ts,%s AS v FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? AND number_v <> %s PARTITION BY tbname",
+			sel.expr, db.DBName, db.SuperTableTv, formatNumber(db.NumberDefault)), deviceId, key, start, end)
+	} else {
+		finder.Append(fmt.Sprintf("SELECT ts,number_v AS v FROM %s.%s WHERE device_id = ? AND k = ? AND ts >= ? AND ts <= ? AND number_v <> %s order by ts asc",
+			db.DBName, db.SuperTableTv, formatNumber(db.NumberDefault)), deviceId, key, start, end)
+	}
+	finder.Append(rowLimit())
+	dataMap, err := db.QueryMap(ctx, finder, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRows(len(dataMap)); err != nil {
+		return nil, err
+	}
+
+	ts := make([]time.Time, 0, len(dataMap))
+	values := make([]interface{}, 0, len(dataMap))
+	for _, v := range dataMap {
+		t, err := timeutil.Parse(v["ts"])
+		if err != nil {
+			return nil, err
+		}
+		ts = append(ts, t)
+		values = append(values, v["v"])
+	}
+	if sel.name == "mstddev" {
+		values = movingStddev(values, sel.k)
+	}
+
+	series := make([]map[string]interface{}, 0, len(values))
+	for i := range values {
+		if values[i] == nil {
+			continue
+		}
+		series = append(series, map[string]interface{}{"x": tf.Format(ts[i]), "y": values[i]})
+	}
+	return series, nil
+}
+
+// 最近k个有效数值点的总体标准差，不足k个点或当前点不是数值时为空
+// 用Welford算法增量更新窗口的均值和平方差和，避免sumSq-mean²的精度损失
+func movingStddev(values []interface{}, k int) []interface{} {
+	ret := make([]interface{}, len(values))
+	window := make([]float64, 0, k)
+	var n, mean, m2 float64
+	for i, v := range values {
+		x, ok := numberValue(v)
+		if !ok || x == db.NumberDefault || math.IsNaN(x) {
+			continue
+		}
+		if len(window) == k {
+			// 移出最早的点
+			old := window[0]
+			window = window[1:]
+			n--
+			if n == 0 {
+				mean, m2 = 0, 0
+			} else {
+				d := old - mean
+				mean -= d / n
+				m2 -= d * (old - mean)
+			}
+		}
+		window = append(window, x)
+		n++
+		d := x - mean
+		mean += d / n
+		m2 += d * (x - mean)
+		if len(window) == k {
+			ret[i] = math.Sqrt(math.Max(m2/n, 0))
+		}
+	}
+	return ret
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"math"
+	"testing"
+
+	db "thingspanel-TDengine/db"
+)
+
+// 直接按定义计算的总体标准差
+func naiveStddev(xs []float64) float64 {
+	var mean float64
+	for _, x := range xs {
+		mean += x
+	}
+	mean /= float64(len(xs))
+	var sq float64
+	for _, x := range xs {
+		sq += (x - mean) * (x - mean)
+	}
+	return math.Sqrt(sq / float64(len(xs)))
+}
+
+func TestMovingStddev(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []interface{}
+		k      int
+		want   []interface{} // nil表示该点无输出，否则为该点对应的窗口数据
+	}{
+		{
+			name:   "full windows",
+			values: []interface{}{1.0, 2.0, 4.0, 8.0},
+			k:      2,
+			want:   []interface{}{nil, []float64{1, 2}, []float64{2, 4}, []float64{4, 8}},
+		},
+		{
+			name:   "k of one is zero",
+			values: []interface{}{3.0, 5.0},
+			k:      1,
+			want:   []interface{}{[]float64{3}, []float64{5}},
+		},
+		{
+			name:   "skips missing and non numeric",
+			values: []interface{}{1.0, nil, "on", float64(db.NumberDefault), 3.0, int64(5)},
+			k:      2,
+			want:   []interface{}{nil, nil, nil, nil, []float64{1, 3}, []float64{3, 5}},
+		},
+		{
+			name:   "fewer points than k",
+			values: []interface{}{1.0, 2.0},
+			k:      3,
+			want:   []interface{}{nil, nil},
+		},
+		{
+			name:   "large offset keeps precision",
+			values: []interface{}{1e9 + 1, 1e9 + 2, 1e9 + 3, 1e9 + 4},
+			k:      3,
+			want:   []interface{}{nil, nil, []float64{1e9 + 1, 1e9 + 2, 1e9 + 3}, []float64{1e9 + 2, 1e9 + 3, 1e9 + 4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := movingStddev(tt.values, tt.k)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d values, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if tt.want[i] == nil {
+					if got[i] != nil {
+						t.Fatalf("value %d = %v, want none", i, got[i])
+					}
+					continue
+				}
+				want := naiveStddev(tt.want[i].([]float64))
+				f, ok := got[i].(float64)
+				if !ok || math.Abs(f-want) > 1e-9 {
+					t.Fatalf("value %d = %v, want %v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMovingFunc(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    movingSelect
+		wantErr bool
+	}{
+		{in: "mavg(5)", want: movingSelect{name: "mavg", expr: "MAVG(number_v, 5)", k: 5}},
+		{in: "csum", want: movingSelect{name: "csum", expr: "CSUM(number_v)"}},
+		{in: "mstddev(10)", want: movingSelect{name: "mstddev", k: 10}},
+		{in: "mstddev", wantErr: true},
+		{in: "mavg(0)", wantErr: true},
+		{in: "mavg(1.5)", wantErr: true},
+		{in: "csum(2)", wantErr: true},
+		{in: "avg", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseMovingFunc(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
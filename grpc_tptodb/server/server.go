@@ -3,13 +3,18 @@ package server
 import (
 	"flag"
 	"fmt"
-	"log"
 	"net"
+	"os"
 
 	pb "thingspanel-TDengine/grpc_tptodb"
+	"thingspanel-TDengine/health"
+	"thingspanel-TDengine/logger"
 
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 // server is used to implement helloworld.GreeterServer.
@@ -29,13 +34,34 @@ func Listen() {
 	flag.Parse()
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
 	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
+		logger.Grpc.Error("failed to listen", "error", err)
+		os.Exit(1)
 	}
-	s := grpc.NewServer()
+	if err := loadAPIKeys(); err != nil {
+		logger.Grpc.Error("failed to read grpc.auth.api_keys", "error", err)
+		os.Exit(1)
+	}
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(tracingUnaryInterceptor, metricsUnaryInterceptor, authUnaryInterceptor, loggingUnaryInterceptor, guardUnaryInterceptor),
+		grpc.ChainStreamInterceptor(tracingStreamInterceptor, metricsStreamInterceptor, authStreamInterceptor, loggingStreamInterceptor),
+	}
+	tlsConfig, err := serverTLSConfig()
+	if err != nil {
+		logger.Grpc.Error("failed to load tls config", "error", err)
+		os.Exit(1)
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		logger.Grpc.Info("tls enabled", "mtls", viper.GetString("grpc.tls.client_ca_file") != "")
+	}
+	s := grpc.NewServer(opts...)
 	pb.RegisterGreeterServer(s, &server{})
 	pb.RegisterThingsPanelServer(s, &server{})
-	log.Printf("server listening at %v", lis.Addr())
+	healthpb.RegisterHealthServer(s, health.Server)
+	reflection.Register(s)
+	logger.Grpc.Info("server listening", "addr", lis.Addr().String())
 	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+		logger.Grpc.Error("failed to serve", "error", err)
+		os.Exit(1)
 	}
 }
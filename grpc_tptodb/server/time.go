@@ -0,0 +1,23 @@
+package server
+
+import (
+	"thingspanel-TDengine/timeutil"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// 带时区和时间格式参数的请求
+type timeRequest interface {
+	GetTimezone() string
+	GetTimeFormat() string
+}
+
+// 按请求参数生成时间格式化器，参数无效时返回InvalidArgument
+func requestFormatter(in timeRequest) (*timeutil.Formatter, error) {
+	tf, err := timeutil.New(in.GetTimezone(), in.GetTimeFormat())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return tf, nil
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"thingspanel-TDengine/logger"
+
+	"github.com/spf13/viper"
+)
+
+// 证书热加载：定时检查证书文件修改时间，变化后重新加载
+// 新连接握手时使用最新的证书和客户端CA
+type certReloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTime  time.Time
+}
+
+func newCertReloader(certFile, keyFile, clientCAFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile, clientCAFile: clientCAFile}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// 证书文件最近的修改时间
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, f := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if f == "" {
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return latest, err
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+func (r *certReloader) load() error {
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if r.clientCAFile != "" {
+		pem, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read client ca: %w", err)
+		}
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in client ca file %s", r.clientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCA = pool
+	r.modTime = modTime
+	r.mu.Unlock()
+	return nil
+}
+
+// 定时检查证书是否轮换
+func (r *certReloader) watch(interval time.Duration) {
+	tc := time.NewTicker(interval)
+	defer tc.Stop()
+	for range tc.C {
+		modTime, err := r.latestModTime()
+		if err != nil {
+			logger.Grpc.Warn("failed to stat certificate files", "error", err)
+			continue
+		}
+		r.mu.RLock()
+		changed := modTime.After(r.modTime)
+		r.mu.RUnlock()
+		if !changed {
+			continue
+		}
+		// 加载失败时继续使用旧证书
+		if err := r.load(); err != nil {
+			logger.Grpc.Error("failed to reload certificate", "error", err)
+			continue
+		}
+		logger.Grpc.Info("certificate reloaded")
+	}
+}
+
+// 每次握手返回当前证书配置
+func (r *certReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*r.cert},
+		NextProtos:   []string{"h2"},
+	}
+	if r.clientCA != nil {
+		cfg.ClientCAs = r.clientCA
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// 根据配置生成服务端TLS配置，未开启时返回nil
+func serverTLSConfig() (*tls.Config, error) {
+	if !viper.GetBool("grpc.tls.enable") {
+		return nil, nil
+	}
+
+	r, err := newCertReloader(
+		viper.GetString("grpc.tls.cert_file"),
+		viper.GetString("grpc.tls.key_file"),
+		viper.GetString("grpc.tls.client_ca_file"))
+	if err != nil {
+		return nil, err
+	}
+
+	interval := viper.GetDuration("grpc.tls.reload_interval") * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	go r.watch(interval)
+
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.getConfigForClient,
+	}, nil
+}
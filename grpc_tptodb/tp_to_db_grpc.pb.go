@@ -129,13 +129,22 @@ var Greeter_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	ThingsPanel_GetDeviceHistory_FullMethodName                = "/tptodb.ThingsPanel/GetDeviceHistory"
-	ThingsPanel_GetDeviceHistoryWithPageAndPage_FullMethodName = "/tptodb.ThingsPanel/GetDeviceHistoryWithPageAndPage"
-	ThingsPanel_GetDeviceAttributesHistory_FullMethodName      = "/tptodb.ThingsPanel/GetDeviceAttributesHistory"
-	ThingsPanel_GetDeviceAttributesCurrents_FullMethodName     = "/tptodb.ThingsPanel/GetDeviceAttributesCurrents"
-	ThingsPanel_GetDeviceAttributesCurrentList_FullMethodName  = "/tptodb.ThingsPanel/GetDeviceAttributesCurrentList"
-	ThingsPanel_GetDeviceKVDataWithNoAggregate_FullMethodName  = "/tptodb.ThingsPanel/GetDeviceKVDataWithNoAggregate"
-	ThingsPanel_GetDeviceKVDataWithAggregate_FullMethodName    = "/tptodb.ThingsPanel/GetDeviceKVDataWithAggregate"
+	ThingsPanel_GetDeviceHistory_FullMethodName                 = "/tptodb.ThingsPanel/GetDeviceHistory"
+	ThingsPanel_GetDeviceHistoryWithPageAndPage_FullMethodName  = "/tptodb.ThingsPanel/GetDeviceHistoryWithPageAndPage"
+	ThingsPanel_GetDeviceAttributesHistory_FullMethodName       = "/tptodb.ThingsPanel/GetDeviceAttributesHistory"
+	ThingsPanel_GetDeviceAttributesCurrents_FullMethodName      = "/tptodb.ThingsPanel/GetDeviceAttributesCurrents"
+	ThingsPanel_GetDeviceAttributesCurrentList_FullMethodName   = "/tptodb.ThingsPanel/GetDeviceAttributesCurrentList"
+	ThingsPanel_GetDeviceKVDataWithNoAggregate_FullMethodName   = "/tptodb.ThingsPanel/GetDeviceKVDataWithNoAggregate"
+	ThingsPanel_GetDeviceKVDataWithAggregate_FullMethodName     = "/tptodb.ThingsPanel/GetDeviceKVDataWithAggregate"
+	ThingsPanel_GetMultiDeviceAttributesCurrents_FullMethodName = "/tptodb.ThingsPanel/GetMultiDeviceAttributesCurrents"
+	ThingsPanel_SubscribeTelemetry_FullMethodName               = "/tptodb.ThingsPanel/SubscribeTelemetry"
+	ThingsPanel_GetGroupKVDataWithAggregate_FullMethodName      = "/tptodb.ThingsPanel/GetGroupKVDataWithAggregate"
+	ThingsPanel_GetDeviceStateStats_FullMethodName              = "/tptodb.ThingsPanel/GetDeviceStateStats"
+	ThingsPanel_GetDeviceThresholdEvents_FullMethodName         = "/tptodb.ThingsPanel/GetDeviceThresholdEvents"
+	ThingsPanel_GetDeviceKVSummary_FullMethodName               = "/tptodb.ThingsPanel/GetDeviceKVSummary"
+	ThingsPanel_GetKeyCatalog_FullMethodName                    = "/tptodb.ThingsPanel/GetKeyCatalog"
+	ThingsPanel_GetDeviceActivity_FullMethodName                = "/tptodb.ThingsPanel/GetDeviceActivity"
+	ThingsPanel_GetDeviceSnapshot_FullMethodName                = "/tptodb.ThingsPanel/GetDeviceSnapshot"
 )
 
 // ThingsPanelClient is the client API for ThingsPanel service.
@@ -152,6 +161,24 @@ type ThingsPanelClient interface {
 	GetDeviceAttributesCurrentList(ctx context.Context, in *GetDeviceAttributesCurrentListRequest, opts ...grpc.CallOption) (*GetDeviceAttributesCurrentListReply, error)
 	GetDeviceKVDataWithNoAggregate(ctx context.Context, in *GetDeviceKVDataWithNoAggregateRequest, opts ...grpc.CallOption) (*GetDeviceKVDataWithNoAggregateReply, error)
 	GetDeviceKVDataWithAggregate(ctx context.Context, in *GetDeviceKVDataWithAggregateRequest, opts ...grpc.CallOption) (*GetDeviceKVDataWithAggregateReply, error)
+	// 批量查询多个设备的当前数据
+	GetMultiDeviceAttributesCurrents(ctx context.Context, in *GetMultiDeviceAttributesCurrentsRequest, opts ...grpc.CallOption) (*GetMultiDeviceAttributesCurrentsReply, error)
+	// 实时订阅设备遥测数据，首条消息为当前值快照
+	SubscribeTelemetry(ctx context.Context, in *SubscribeTelemetryRequest, opts ...grpc.CallOption) (ThingsPanel_SubscribeTelemetryClient, error)
+	// 按设备列表、租户或模型跨设备聚合
+	GetGroupKVDataWithAggregate(ctx context.Context, in *GetGroupKVDataWithAggregateRequest, opts ...grpc.CallOption) (*GetGroupKVDataWithAggregateReply, error)
+	// bool、字符串key的状态时长和切换次数统计
+	GetDeviceStateStats(ctx context.Context, in *GetDeviceStateStatsRequest, opts ...grpc.CallOption) (*GetDeviceStateStatsReply, error)
+	// 阈值越限事件查询
+	GetDeviceThresholdEvents(ctx context.Context, in *GetDeviceThresholdEventsRequest, opts ...grpc.CallOption) (*GetDeviceThresholdEventsReply, error)
+	// 多key统计汇总
+	GetDeviceKVSummary(ctx context.Context, in *GetDeviceKVSummaryRequest, opts ...grpc.CallOption) (*GetDeviceKVSummaryReply, error)
+	// 设备或模型的key目录
+	GetKeyCatalog(ctx context.Context, in *GetKeyCatalogRequest, opts ...grpc.CallOption) (*GetKeyCatalogReply, error)
+	// 设备活跃度和在线状态
+	GetDeviceActivity(ctx context.Context, in *GetDeviceActivityRequest, opts ...grpc.CallOption) (*GetDeviceActivityReply, error)
+	// 设备在某一时刻的各key取值
+	GetDeviceSnapshot(ctx context.Context, in *GetDeviceSnapshotRequest, opts ...grpc.CallOption) (*GetDeviceSnapshotReply, error)
 }
 
 type thingsPanelClient struct {
@@ -225,6 +252,110 @@ func (c *thingsPanelClient) GetDeviceKVDataWithAggregate(ctx context.Context, in
 	return out, nil
 }
 
+func (c *thingsPanelClient) GetMultiDeviceAttributesCurrents(ctx context.Context, in *GetMultiDeviceAttributesCurrentsRequest, opts ...grpc.CallOption) (*GetMultiDeviceAttributesCurrentsReply, error) {
+	out := new(GetMultiDeviceAttributesCurrentsReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetMultiDeviceAttributesCurrents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsPanelClient) SubscribeTelemetry(ctx context.Context, in *SubscribeTelemetryRequest, opts ...grpc.CallOption) (ThingsPanel_SubscribeTelemetryClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ThingsPanel_ServiceDesc.Streams[0], ThingsPanel_SubscribeTelemetry_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &thingsPanelSubscribeTelemetryClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ThingsPanel_SubscribeTelemetryClient interface {
+	Recv() (*SubscribeTelemetryReply, error)
+	grpc.ClientStream
+}
+
+type thingsPanelSubscribeTelemetryClient struct {
+	grpc.ClientStream
+}
+
+func (x *thingsPanelSubscribeTelemetryClient) Recv() (*SubscribeTelemetryReply, error) {
+	m := new(SubscribeTelemetryReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *thingsPanelClient) GetGroupKVDataWithAggregate(ctx context.Context, in *GetGroupKVDataWithAggregateRequest, opts ...grpc.CallOption) (*GetGroupKVDataWithAggregateReply, error) {
+	out := new(GetGroupKVDataWithAggregateReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetGroupKVDataWithAggregate_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsPanelClient) GetDeviceStateStats(ctx context.Context, in *GetDeviceStateStatsRequest, opts ...grpc.CallOption) (*GetDeviceStateStatsReply, error) {
+	out := new(GetDeviceStateStatsReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetDeviceStateStats_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsPanelClient) GetDeviceThresholdEvents(ctx context.Context, in *GetDeviceThresholdEventsRequest, opts ...grpc.CallOption) (*GetDeviceThresholdEventsReply, error) {
+	out := new(GetDeviceThresholdEventsReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetDeviceThresholdEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsPanelClient) GetDeviceKVSummary(ctx context.Context, in *GetDeviceKVSummaryRequest, opts ...grpc.CallOption) (*GetDeviceKVSummaryReply, error) {
+	out := new(GetDeviceKVSummaryReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetDeviceKVSummary_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsPanelClient) GetKeyCatalog(ctx context.Context, in *GetKeyCatalogRequest, opts ...grpc.CallOption) (*GetKeyCatalogReply, error) {
+	out := new(GetKeyCatalogReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetKeyCatalog_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsPanelClient) GetDeviceActivity(ctx context.Context, in *GetDeviceActivityRequest, opts ...grpc.CallOption) (*GetDeviceActivityReply, error) {
+	out := new(GetDeviceActivityReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetDeviceActivity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *thingsPanelClient) GetDeviceSnapshot(ctx context.Context, in *GetDeviceSnapshotRequest, opts ...grpc.CallOption) (*GetDeviceSnapshotReply, error) {
+	out := new(GetDeviceSnapshotReply)
+	err := c.cc.Invoke(ctx, ThingsPanel_GetDeviceSnapshot_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ThingsPanelServer is the server API for ThingsPanel service.
 // All implementations must embed UnimplementedThingsPanelServer
 // for forward compatibility
@@ -239,6 +370,24 @@ type ThingsPanelServer interface {
 	GetDeviceAttributesCurrentList(context.Context, *GetDeviceAttributesCurrentListRequest) (*GetDeviceAttributesCurrentListReply, error)
 	GetDeviceKVDataWithNoAggregate(context.Context, *GetDeviceKVDataWithNoAggregateRequest) (*GetDeviceKVDataWithNoAggregateReply, error)
 	GetDeviceKVDataWithAggregate(context.Context, *GetDeviceKVDataWithAggregateRequest) (*GetDeviceKVDataWithAggregateReply, error)
+	// 批量查询多个设备的当前数据
+	GetMultiDeviceAttributesCurrents(context.Context, *GetMultiDeviceAttributesCurrentsRequest) (*GetMultiDeviceAttributesCurrentsReply, error)
+	// 实时订阅设备遥测数据，首条消息为当前值快照
+	SubscribeTelemetry(*SubscribeTelemetryRequest, ThingsPanel_SubscribeTelemetryServer) error
+	// 按设备列表、租户或模型跨设备聚合
+	GetGroupKVDataWithAggregate(context.Context, *GetGroupKVDataWithAggregateRequest) (*GetGroupKVDataWithAggregateReply, error)
+	// bool、字符串key的状态时长和切换次数统计
+	GetDeviceStateStats(context.Context, *GetDeviceStateStatsRequest) (*GetDeviceStateStatsReply, error)
+	// 阈值越限事件查询
+	GetDeviceThresholdEvents(context.Context, *GetDeviceThresholdEventsRequest) (*GetDeviceThresholdEventsReply, error)
+	// 多key统计汇总
+	GetDeviceKVSummary(context.Context, *GetDeviceKVSummaryRequest) (*GetDeviceKVSummaryReply, error)
+	// 设备或模型的key目录
+	GetKeyCatalog(context.Context, *GetKeyCatalogRequest) (*GetKeyCatalogReply, error)
+	// 设备活跃度和在线状态
+	GetDeviceActivity(context.Context, *GetDeviceActivityRequest) (*GetDeviceActivityReply, error)
+	// 设备在某一时刻的各key取值
+	GetDeviceSnapshot(context.Context, *GetDeviceSnapshotRequest) (*GetDeviceSnapshotReply, error)
 	mustEmbedUnimplementedThingsPanelServer()
 }
 
@@ -267,6 +416,33 @@ func (UnimplementedThingsPanelServer) GetDeviceKVDataWithNoAggregate(context.Con
 func (UnimplementedThingsPanelServer) GetDeviceKVDataWithAggregate(context.Context, *GetDeviceKVDataWithAggregateRequest) (*GetDeviceKVDataWithAggregateReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceKVDataWithAggregate not implemented")
 }
+func (UnimplementedThingsPanelServer) GetMultiDeviceAttributesCurrents(context.Context, *GetMultiDeviceAttributesCurrentsRequest) (*GetMultiDeviceAttributesCurrentsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMultiDeviceAttributesCurrents not implemented")
+}
+func (UnimplementedThingsPanelServer) SubscribeTelemetry(*SubscribeTelemetryRequest, ThingsPanel_SubscribeTelemetryServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeTelemetry not implemented")
+}
+func (UnimplementedThingsPanelServer) GetGroupKVDataWithAggregate(context.Context, *GetGroupKVDataWithAggregateRequest) (*GetGroupKVDataWithAggregateReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGroupKVDataWithAggregate not implemented")
+}
+func (UnimplementedThingsPanelServer) GetDeviceStateStats(context.Context, *GetDeviceStateStatsRequest) (*GetDeviceStateStatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceStateStats not implemented")
+}
+func (UnimplementedThingsPanelServer) GetDeviceThresholdEvents(context.Context, *GetDeviceThresholdEventsRequest) (*GetDeviceThresholdEventsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceThresholdEvents not implemented")
+}
+func (UnimplementedThingsPanelServer) GetDeviceKVSummary(context.Context, *GetDeviceKVSummaryRequest) (*GetDeviceKVSummaryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceKVSummary not implemented")
+}
+func (UnimplementedThingsPanelServer) GetKeyCatalog(context.Context, *GetKeyCatalogRequest) (*GetKeyCatalogReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKeyCatalog not implemented")
+}
+func (UnimplementedThingsPanelServer) GetDeviceActivity(context.Context, *GetDeviceActivityRequest) (*GetDeviceActivityReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceActivity not implemented")
+}
+func (UnimplementedThingsPanelServer) GetDeviceSnapshot(context.Context, *GetDeviceSnapshotRequest) (*GetDeviceSnapshotReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeviceSnapshot not implemented")
+}
 func (UnimplementedThingsPanelServer) mustEmbedUnimplementedThingsPanelServer() {}
 
 // UnsafeThingsPanelServer may be embedded to opt out of forward compatibility for this service.
@@ -406,6 +582,171 @@ func _ThingsPanel_GetDeviceKVDataWithAggregate_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ThingsPanel_GetMultiDeviceAttributesCurrents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMultiDeviceAttributesCurrentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetMultiDeviceAttributesCurrents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetMultiDeviceAttributesCurrents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetMultiDeviceAttributesCurrents(ctx, req.(*GetMultiDeviceAttributesCurrentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsPanel_SubscribeTelemetry_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTelemetryRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ThingsPanelServer).SubscribeTelemetry(m, &thingsPanelSubscribeTelemetryServer{stream})
+}
+
+type ThingsPanel_SubscribeTelemetryServer interface {
+	Send(*SubscribeTelemetryReply) error
+	grpc.ServerStream
+}
+
+type thingsPanelSubscribeTelemetryServer struct {
+	grpc.ServerStream
+}
+
+func (x *thingsPanelSubscribeTelemetryServer) Send(m *SubscribeTelemetryReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ThingsPanel_GetGroupKVDataWithAggregate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetGroupKVDataWithAggregateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetGroupKVDataWithAggregate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetGroupKVDataWithAggregate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetGroupKVDataWithAggregate(ctx, req.(*GetGroupKVDataWithAggregateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsPanel_GetDeviceStateStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceStateStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetDeviceStateStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetDeviceStateStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetDeviceStateStats(ctx, req.(*GetDeviceStateStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsPanel_GetDeviceThresholdEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceThresholdEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetDeviceThresholdEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetDeviceThresholdEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetDeviceThresholdEvents(ctx, req.(*GetDeviceThresholdEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsPanel_GetDeviceKVSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceKVSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetDeviceKVSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetDeviceKVSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetDeviceKVSummary(ctx, req.(*GetDeviceKVSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsPanel_GetKeyCatalog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyCatalogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetKeyCatalog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetKeyCatalog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetKeyCatalog(ctx, req.(*GetKeyCatalogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsPanel_GetDeviceActivity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceActivityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetDeviceActivity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetDeviceActivity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetDeviceActivity(ctx, req.(*GetDeviceActivityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ThingsPanel_GetDeviceSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeviceSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ThingsPanelServer).GetDeviceSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ThingsPanel_GetDeviceSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ThingsPanelServer).GetDeviceSnapshot(ctx, req.(*GetDeviceSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ThingsPanel_ServiceDesc is the grpc.ServiceDesc for ThingsPanel service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -441,7 +782,45 @@ var ThingsPanel_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetDeviceKVDataWithAggregate",
 			Handler:    _ThingsPanel_GetDeviceKVDataWithAggregate_Handler,
 		},
+		{
+			MethodName: "GetMultiDeviceAttributesCurrents",
+			Handler:    _ThingsPanel_GetMultiDeviceAttributesCurrents_Handler,
+		},
+		{
+			MethodName: "GetGroupKVDataWithAggregate",
+			Handler:    _ThingsPanel_GetGroupKVDataWithAggregate_Handler,
+		},
+		{
+			MethodName: "GetDeviceStateStats",
+			Handler:    _ThingsPanel_GetDeviceStateStats_Handler,
+		},
+		{
+			MethodName: "GetDeviceThresholdEvents",
+			Handler:    _ThingsPanel_GetDeviceThresholdEvents_Handler,
+		},
+		{
+			MethodName: "GetDeviceKVSummary",
+			Handler:    _ThingsPanel_GetDeviceKVSummary_Handler,
+		},
+		{
+			MethodName: "GetKeyCatalog",
+			Handler:    _ThingsPanel_GetKeyCatalog_Handler,
+		},
+		{
+			MethodName: "GetDeviceActivity",
+			Handler:    _ThingsPanel_GetDeviceActivity_Handler,
+		},
+		{
+			MethodName: "GetDeviceSnapshot",
+			Handler:    _ThingsPanel_GetDeviceSnapshot_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeTelemetry",
+			Handler:       _ThingsPanel_SubscribeTelemetry_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "tp_to_db.proto",
 }
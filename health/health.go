@@ -0,0 +1,93 @@
+package health
+
+import (
+	"sync/atomic"
+	"time"
+
+	"thingspanel-TDengine/db"
+	"thingspanel-TDengine/logger"
+
+	"github.com/spf13/viper"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// grpc.health.v1 中的服务名
+const (
+	ServiceIngest = "ingest" // MQTT已连接并订阅
+	ServiceQuery  = "query"  // 数据库可访问
+)
+
+// gRPC健康检查服务
+var Server = grpchealth.NewServer()
+
+var ingestReady, queryReady atomic.Bool
+
+func init() {
+	Server.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	Server.SetServingStatus(ServiceIngest, healthpb.HealthCheckResponse_NOT_SERVING)
+	Server.SetServingStatus(ServiceQuery, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// 启动数据库定时检查
+func HealthInit() {
+	interval := viper.GetDuration("health.db_check_interval") * time.Second
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	checkDb()
+	go func() {
+		tc := time.NewTicker(interval)
+		defer tc.Stop()
+		for range tc.C {
+			checkDb()
+		}
+	}()
+}
+
+func checkDb() {
+	if err := db.Ping(); err != nil {
+		logger.Db.Warn("db ping failed", "error", err)
+		SetQuery(false)
+		return
+	}
+	SetQuery(true)
+}
+
+// 设置数据接入状态
+func SetIngest(ok bool) {
+	ingestReady.Store(ok)
+	Server.SetServingStatus(ServiceIngest, servingStatus(ok))
+	update()
+}
+
+// 设置数据查询状态
+func SetQuery(ok bool) {
+	queryReady.Store(ok)
+	Server.SetServingStatus(ServiceQuery, servingStatus(ok))
+	update()
+}
+
+// 接入和查询都正常时就绪
+func Ready() bool {
+	return ingestReady.Load() && queryReady.Load()
+}
+
+// 各服务状态
+func Status() map[string]bool {
+	return map[string]bool{
+		ServiceIngest: ingestReady.Load(),
+		ServiceQuery:  queryReady.Load(),
+	}
+}
+
+func update() {
+	Server.SetServingStatus("", servingStatus(Ready()))
+}
+
+func servingStatus(ok bool) healthpb.HealthCheckResponse_ServingStatus {
+	if ok {
+		return healthpb.HealthCheckResponse_SERVING
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING
+}
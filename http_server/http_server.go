@@ -0,0 +1,50 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"thingspanel-TDengine/health"
+	"thingspanel-TDengine/logger"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/viper"
+)
+
+// 探针、监控指标等HTTP接口
+var Mux = http.NewServeMux()
+
+func HttpInit() {
+	Mux.HandleFunc("/healthz", healthz)
+	Mux.HandleFunc("/readyz", readyz)
+	Mux.Handle("/metrics", promhttp.Handler())
+	go Listen()
+}
+
+func Listen() {
+	addr := fmt.Sprintf(":%d", viper.GetInt("http.port"))
+	logger.Http.Info("http server listening", "addr", addr)
+	if err := http.ListenAndServe(addr, Mux); err != nil {
+		logger.Http.Error("failed to serve http", "error", err)
+		os.Exit(1)
+	}
+}
+
+// 存活探针：进程可以处理请求即返回200
+func healthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// 就绪探针：MQTT已订阅且数据库可访问时返回200，否则503
+func readyz(w http.ResponseWriter, r *http.Request) {
+	code := http.StatusOK
+	if !health.Ready() {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(health.Status())
+}
@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// 各子系统日志，级别可通过log.levels.<子系统>单独配置
+var (
+	Mqtt    = slog.Default().With("subsystem", "mqtt")
+	Db      = slog.Default().With("subsystem", "db")
+	Grpc    = slog.Default().With("subsystem", "grpc")
+	Http    = slog.Default().With("subsystem", "http")
+	Tracing = slog.Default().With("subsystem", "tracing")
+)
+
+// 初始化日志：log.format为json或text，log.level为默认级别
+// 消息内容(payload)只在debug级别输出
+func LoggerInit() {
+	h := newHandler(os.Stdout, parseLevel(viper.GetString("log.level"), slog.LevelInfo))
+	slog.SetDefault(slog.New(h))
+
+	Mqtt = subsystem(h, "mqtt")
+	Db = subsystem(h, "db")
+	Grpc = subsystem(h, "grpc")
+	Http = subsystem(h, "http")
+	Tracing = subsystem(h, "tracing")
+}
+
+func newHandler(w io.Writer, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if viper.GetString("log.format") == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+// 子系统未单独配置级别时使用默认级别
+func subsystem(h slog.Handler, name string) *slog.Logger {
+	key := "log.levels." + name
+	if viper.IsSet(key) {
+		h = &levelHandler{Handler: h, level: parseLevel(viper.GetString(key), slog.LevelInfo)}
+	}
+	return slog.New(h).With("subsystem", name)
+}
+
+func parseLevel(s string, def slog.Level) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		if s != "" {
+			log.Printf("invalid log level %q, using %s", s, def)
+		}
+		return def
+	}
+	return level
+}
+
+// 按子系统级别过滤，替换外层handler的级别
+type levelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func (h *levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+type ctxKey struct{}
+
+// 将带请求字段的日志写入上下文
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// 从上下文获取请求日志，没有时返回gRPC子系统日志
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return Grpc
+}
@@ -1,23 +1,50 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"thingspanel-TDengine/db"
 	"thingspanel-TDengine/grpc_tptodb/server"
+	"thingspanel-TDengine/health"
+	httpserver "thingspanel-TDengine/http_server"
+	"thingspanel-TDengine/logger"
 
 	mqttclient "thingspanel-TDengine/mqtt_client"
+	"thingspanel-TDengine/timeutil"
+	"thingspanel-TDengine/tracing"
 
 	"github.com/spf13/viper"
 )
 
 func main() {
 	initConf()            // Viper初始化配置
+	logger.LoggerInit()   // 初始化日志
+	timeutil.TimeInit()   // 初始化时区和时间格式
+	tracing.TracingInit() // 初始化链路追踪
 	db.InitDb()           // 初始化数据库
+	health.HealthInit()   // 启动健康检查
 	mqttclient.MqttInit() // 启动mqtt客户端
 	server.GrpcInit()     // 启动grpc服务
-	select {}
+	httpserver.HttpInit() // 启动http服务(探针)
+
+	// 收到退出信号后写完缓冲中的数据，再上报剩余span
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	slog.Info("shutting down", "signal", sig.String())
+	mqttclient.ShutDown()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := tracing.Shutdown(ctx); err != nil {
+		slog.Error("failed to shutdown tracing", "error", err)
+	}
 }
 
 // Viper初始化配置
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "tptdengine"
+
+// 数据接入
+var (
+	MessagesReceived = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mqtt",
+		Name:      "messages_received_total",
+		Help:      "MQTT messages received.",
+	})
+	MessagesDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mqtt",
+		Name:      "messages_dropped_total",
+		Help:      "MQTT messages dropped because the write channel was full.",
+	})
+	DecodeFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mqtt",
+		Name:      "decode_failures_total",
+		Help:      "MQTT messages that could not be decoded.",
+	})
+)
+
+// 写入通道长度在采集时读取，空闲时也不会过期
+func RegisterChannelDepth(depth func() int) {
+	promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "mqtt",
+		Name:      "channel_depth",
+		Help:      "Messages waiting in the write channel.",
+	}, func() float64 { return float64(depth()) })
+}
+
+// 数据写入
+var (
+	RowsInserted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "rows_inserted_total",
+		Help:      "Rows inserted into ts_kv.",
+	})
+	RowsFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "rows_failed_total",
+		Help:      "Rows that failed to insert into ts_kv.",
+	})
+	BatchSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "batch_size",
+		Help:      "Rows per insert batch.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	FlushLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "flush_duration_seconds",
+		Help:      "Time to flush one insert batch, including subtable creation.",
+		Buckets:   prometheus.DefBuckets,
+	})
+	SubtableCreates = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "subtable_creates_total",
+		Help:      "Subtables created by this process.",
+	})
+	QueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "TDengine query duration.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"status"})
+)
+
+// gRPC
+var (
+	RpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC request duration by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+	RpcErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_errors_total",
+		Help:      "gRPC requests that returned an error, by method and code.",
+	}, []string{"method", "code"})
+)
@@ -3,8 +3,6 @@ package mqttclient
 import (
 	"context"
 	"encoding/json"
-	"fmt"
-	"log"
 	"path"
 	"sync"
 	"sync/atomic"
@@ -14,18 +12,27 @@ import (
 	"github.com/spf13/viper"
 
 	db "thingspanel-TDengine/db"
+	"thingspanel-TDengine/health"
+	"thingspanel-TDengine/logger"
+	"thingspanel-TDengine/metrics"
+	"thingspanel-TDengine/pubsub"
+	"thingspanel-TDengine/tracing"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var count int64
 var wg *sync.WaitGroup
 var ctx context.Context
 var c context.CancelFunc
+var messages chan db.Message
 
 type mqttPayload struct {
 	Token    string `json:"token"`
 	DeviceId string `json:"device_id"`
+	TenantId string `json:"tenant_id"`
 	Values   []byte `json:"values"`
 }
 
@@ -35,78 +42,54 @@ func GenTopic(topic string) string {
 }
 
 func MqttInit() {
-	fmt.Println("init mqtt_client")
+	logger.Mqtt.Info("init mqtt_client")
 	Connect() // 连接MQTT服务器
-	fmt.Println("init mqtt_client success")
+	logger.Mqtt.Info("init mqtt_client success")
 
 }
 
 // 连接MQTT服务器
 func Connect() {
-	var c mqtt.Client
-	// 掉线重连
-	var connectLostHandler mqtt.ConnectionLostHandler = func(client mqtt.Client, err error) {
-		fmt.Printf("Mqtt Connect lost: %v", err)
-		i := 0
-		for {
-
-			time.Sleep(5 * time.Second)
-			if !c.IsConnectionOpen() {
-				i++
-				fmt.Println("Mqtt客户端掉线重连...", i)
-				if token := c.Connect(); token.Wait() && token.Error() != nil {
-					fmt.Println("Mqtt客户端连接失败...")
-				} else {
-					SubscribeTopic(c)
-					break
-				}
-			} else {
-				break
-			}
-		}
-	}
 	opts := mqtt.NewClientOptions()
 	opts.SetClientID(uuid.New().String()) //设置客户端ID
 	opts.SetUsername(viper.GetString("mqtt.username"))
 	opts.SetPassword(viper.GetString("mqtt.password"))
-	fmt.Println("MQTT连接地址", viper.GetString("mqtt.host")+":"+viper.GetString("mqtt.port"))
+	logger.Mqtt.Info("mqtt broker", "addr", viper.GetString("mqtt.host")+":"+viper.GetString("mqtt.port"))
 	opts.AddBroker(viper.GetString("mqtt.host") + ":" + viper.GetString("mqtt.port"))
-	opts.SetAutoReconnect(true)                //设置自动重连
-	opts.SetOrderMatters(false)                //设置为false，表示订阅的消息可以接收到所有的消息，不管订阅的顺序
-	opts.OnConnectionLost = connectLostHandler //设置连接丢失的处理事件
+	opts.SetAutoReconnect(true)                   //设置自动重连
+	opts.SetConnectRetry(true)                    //首次连接失败时重试
+	opts.SetConnectRetryInterval(5 * time.Second) //首次连接重试间隔
+	opts.SetMaxReconnectInterval(5 * time.Second) //自动重连最大间隔
+	opts.SetOrderMatters(false)                   //设置为false，表示订阅的消息可以接收到所有的消息，不管订阅的顺序
+	opts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		logger.Mqtt.Warn("mqtt connection lost", "error", err)
+		health.SetIngest(false)
+	}) //设置连接丢失的处理事件，重连由客户端自动完成
+	opts.SetReconnectingHandler(func(_ mqtt.Client, _ *mqtt.ClientOptions) {
+		logger.Mqtt.Info("mqtt reconnecting")
+	})
+	// 首次连接和每次自动重连成功后都重新订阅
 	opts.SetOnConnectHandler(func(c mqtt.Client) {
-		fmt.Println("Mqtt客户端已连接")
+		logger.Mqtt.Info("mqtt connected")
+		SubscribeTopic(c)
 	}) //设置连接成功处理事件
 
-	reconnectNumber := 0 //重连次数
-	go func() {
-		for { // 失败重连
-			c = mqtt.NewClient(opts)
-			if token := c.Connect(); token.Wait() && token.Error() != nil {
-				reconnectNumber++
-				fmt.Println("错误说明：", token.Error().Error())
-				fmt.Println("Mqtt客户端连接失败...重试", reconnectNumber)
-			} else {
-				SubscribeTopic(c)
-				break
-			}
-			time.Sleep(5 * time.Second)
-		}
-	}()
+	startWriters()
+	mqtt.NewClient(opts).Connect()
 }
 
 func ShutDown() {
 	c()
 	wg.Wait()
-	log.Printf("ShutDown count: %+v\n", atomic.LoadInt64(&count))
+	logger.Mqtt.Info("shutdown", "count", atomic.LoadInt64(&count))
 }
 
-// 订阅主题
-func SubscribeTopic(client mqtt.Client) {
-	// 启动批量写入
+// 启动批量写入协程，通道和写入协程只创建一次，重连时复用
+func startWriters() {
 	// 通道缓冲区大小
 	var channelBufferSize = viper.GetInt("db.channel_buffer_size")
-	messages := make(chan map[string]interface{}, channelBufferSize)
+	messages = make(chan db.Message, channelBufferSize)
+	metrics.RegisterChannelDepth(func() int { return len(messages) })
 	// 写入协程数
 	var writeWorkers = viper.GetInt("db.write_workers")
 
@@ -117,27 +100,38 @@ func SubscribeTopic(client mqtt.Client) {
 		w := &db.Worker{}
 		go w.Bulk_inset_struct(wg, ctx, messages)
 	}
+}
 
+// 订阅主题
+func SubscribeTopic(client mqtt.Client) {
 	// 设置消息回调处理函数
 	var qos byte = byte(viper.GetUint("mqtt.qos"))
 	topic := viper.GetString("mqtt.attribute_topic")
 	topic = GenTopic(topic)
-	fmt.Print("topic: ", topic)
 	token := client.Subscribe(topic, qos, func(client mqtt.Client, msg mqtt.Message) {
 		messageHandler(messages, client, msg)
 	})
 	if token.Wait() && token.Error() != nil {
-		fmt.Println("订阅失败")
+		logger.Mqtt.Error("failed to subscribe", "topic", topic, "error", token.Error())
+		health.SetIngest(false)
 		return
 	}
-	fmt.Printf("Subscribed to topic: %s\n", topic)
+	logger.Mqtt.Info("subscribed", "topic", topic)
+	health.SetIngest(true)
 }
 
 // 消息处理函数
-func messageHandler(messages chan<- map[string]interface{}, _ mqtt.Client, msg mqtt.Message) {
+func messageHandler(messages chan<- db.Message, _ mqtt.Client, msg mqtt.Message) {
+	metrics.MessagesReceived.Inc()
+	_, span := tracing.Start(context.Background(), "mqtt.message", trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("messaging.destination.name", msg.Topic())))
+	defer span.End()
+
 	payload := &mqttPayload{}
 	if err := json.Unmarshal(msg.Payload(), &payload); err != nil {
-		log.Printf("Failed to unmarshal MQTT message: %v", err)
+		logger.Mqtt.Warn("failed to unmarshal message", "topic", msg.Topic(), "error", err)
+		metrics.DecodeFailures.Inc()
+		span.RecordError(err)
 		return
 	}
 
@@ -146,25 +140,56 @@ func messageHandler(messages chan<- map[string]interface{}, _ mqtt.Client, msg m
 	if len(payload.DeviceId) > 0 {
 		deviceID = payload.DeviceId
 	} else {
-		log.Printf("not exist device_id in payload")
+		logger.Mqtt.Warn("device_id not exist in payload", "topic", msg.Topic())
 		return
 	}
 
+	span.SetAttributes(attribute.String("device_id", deviceID))
+
 	var valuesMap map[string]interface{}
 	//byte转map
 	if err := json.Unmarshal(payload.Values, &valuesMap); err != nil {
-		log.Printf("Failed to unmarshal MQTT message: %v", err)
+		logger.Mqtt.Warn("failed to unmarshal values", "device_id", deviceID, "tenant_id", payload.TenantId, "error", err)
+		metrics.DecodeFailures.Inc()
 		return
 	}
 
-	valuesMap["device_id"] = deviceID
+	dropReservedKeys(deviceID, valuesMap)
+
+	// 有实时订阅者时，在写入通道前生成推送点
+	var points []pubsub.Point
+	if pubsub.HasSubscribers(deviceID) {
+		now := time.Now()
+		for k, v := range valuesMap {
+			points = append(points, pubsub.Point{DeviceId: deviceID, Key: k, Ts: now, Value: v})
+		}
+	}
+
+	message := db.Message{DeviceId: deviceID, TenantId: payload.TenantId, Values: valuesMap, SpanContext: span.SpanContext()}
 
 	select {
-	case messages <- valuesMap:
+	case messages <- message:
 		// atomic.AddInt64(&count, 1)
+		if len(points) > 0 {
+			pubsub.Publish(points)
+		}
 	default:
-		log.Printf("can not write msg:%+v\n", valuesMap)
+		// 通道满时只记录设备，消息内容仅在debug级别输出
+		logger.Mqtt.Warn("write channel full, message dropped", "device_id", deviceID, "tenant_id", payload.TenantId)
+		logger.Mqtt.Debug("dropped message", "device_id", deviceID, "values", valuesMap)
+		metrics.MessagesDropped.Inc()
+		span.AddEvent("dropped, channel full")
 	}
 
 	// log.Printf("count: %+v\n", atomic.LoadInt64(&count))
 }
+
+// 去掉与写入行字段同名的key，设备不能通过上报的值指定自己的租户
+func dropReservedKeys(deviceID string, values map[string]interface{}) {
+	for _, k := range db.ReservedKeys {
+		if _, ok := values[k]; ok {
+			logger.Mqtt.Warn("reserved key in values dropped", "device_id", deviceID, "key", k)
+			delete(values, k)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package mqttclient
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDropReservedKeys(t *testing.T) {
+	values := map[string]interface{}{"temp": 21.5, "tenant_id": "other", "device_id": "d2"}
+	dropReservedKeys("d1", values)
+	if want := map[string]interface{}{"temp": 21.5}; !reflect.DeepEqual(values, want) {
+		t.Fatalf("got %v, want %v", values, want)
+	}
+}
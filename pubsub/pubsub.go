@@ -0,0 +1,129 @@
+package pubsub
+
+import (
+	"sync"
+	"time"
+)
+
+// 实时遥测点
+type Point struct {
+	DeviceId string
+	Key      string
+	Ts       time.Time
+	Value    interface{}
+}
+
+// 订阅者，C缓冲区满时会被剔除(慢消费者)
+type Subscriber struct {
+	C       chan Point
+	devices []string
+	keys    map[string]struct{}
+	evicted chan struct{}
+	once    sync.Once
+}
+
+// 订阅者被剔除时关闭
+func (sub *Subscriber) Evicted() <-chan struct{} {
+	return sub.evicted
+}
+
+func (sub *Subscriber) match(key string) bool {
+	if len(sub.keys) == 0 {
+		return true
+	}
+	_, ok := sub.keys[key]
+	return ok
+}
+
+var (
+	mu       sync.RWMutex
+	byDevice = make(map[string]map[*Subscriber]struct{})
+)
+
+// 订阅设备的遥测数据，keys为空时订阅全部key
+func Subscribe(deviceIds []string, keys []string, bufferSize int) *Subscriber {
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+	sub := &Subscriber{
+		C:       make(chan Point, bufferSize),
+		devices: deviceIds,
+		keys:    make(map[string]struct{}, len(keys)),
+		evicted: make(chan struct{}),
+	}
+	for _, k := range keys {
+		if k != "" {
+			sub.keys[k] = struct{}{}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, id := range deviceIds {
+		subs, ok := byDevice[id]
+		if !ok {
+			subs = make(map[*Subscriber]struct{})
+			byDevice[id] = subs
+		}
+		subs[sub] = struct{}{}
+	}
+	return sub
+}
+
+// 取消订阅
+func Unsubscribe(sub *Subscriber) {
+	mu.Lock()
+	defer mu.Unlock()
+	remove(sub)
+}
+
+func remove(sub *Subscriber) {
+	for _, id := range sub.devices {
+		if subs, ok := byDevice[id]; ok {
+			delete(subs, sub)
+			if len(subs) == 0 {
+				delete(byDevice, id)
+			}
+		}
+	}
+}
+
+// 设备是否有订阅者
+func HasSubscribers(deviceId string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(byDevice[deviceId]) > 0
+}
+
+// 推送遥测点，不阻塞；缓冲区已满的订阅者会被剔除
+func Publish(points []Point) {
+	var slow []*Subscriber
+
+	mu.RLock()
+	for _, p := range points {
+		for sub := range byDevice[p.DeviceId] {
+			if !sub.match(p.Key) {
+				continue
+			}
+			select {
+			case sub.C <- p:
+			default:
+				slow = append(slow, sub)
+			}
+		}
+	}
+	mu.RUnlock()
+
+	if len(slow) == 0 {
+		return
+	}
+
+	mu.Lock()
+	for _, sub := range slow {
+		remove(sub)
+	}
+	mu.Unlock()
+	for _, sub := range slow {
+		sub.once.Do(func() { close(sub.evicted) })
+	}
+}
@@ -0,0 +1,86 @@
+package pubsub
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPublishEvictsSlowSubscriber(t *testing.T) {
+	tests := []struct {
+		name        string
+		bufferSize  int
+		points      int
+		wantEvicted bool
+		wantQueued  int
+	}{
+		{name: "fits buffer", bufferSize: 2, points: 2, wantEvicted: false, wantQueued: 2},
+		{name: "overflows buffer", bufferSize: 2, points: 3, wantEvicted: true, wantQueued: 2},
+		{name: "zero buffer defaults to one", bufferSize: 0, points: 2, wantEvicted: true, wantQueued: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := Subscribe([]string{"dev-" + tt.name}, nil, tt.bufferSize)
+			defer Unsubscribe(sub)
+
+			var points []Point
+			for i := 0; i < tt.points; i++ {
+				points = append(points, Point{DeviceId: "dev-" + tt.name, Key: "temp", Ts: time.Unix(int64(i), 0), Value: i})
+			}
+			Publish(points)
+
+			evicted := false
+			select {
+			case <-sub.Evicted():
+				evicted = true
+			default:
+			}
+			if evicted != tt.wantEvicted {
+				t.Fatalf("evicted = %v, want %v", evicted, tt.wantEvicted)
+			}
+			if got := len(sub.C); got != tt.wantQueued {
+				t.Fatalf("queued = %d, want %d", got, tt.wantQueued)
+			}
+			if got := HasSubscribers("dev-" + tt.name); got == tt.wantEvicted {
+				t.Fatalf("HasSubscribers = %v after eviction=%v", got, tt.wantEvicted)
+			}
+		})
+	}
+}
+
+func TestPublishEvictedOnlyOnce(t *testing.T) {
+	sub := Subscribe([]string{"dev-a", "dev-b"}, nil, 1)
+	// 两个设备同时溢出，evicted只关闭一次
+	Publish([]Point{
+		{DeviceId: "dev-a", Key: "k"}, {DeviceId: "dev-b", Key: "k"},
+		{DeviceId: "dev-a", Key: "k"}, {DeviceId: "dev-b", Key: "k"},
+	})
+	<-sub.Evicted()
+	if HasSubscribers("dev-a") || HasSubscribers("dev-b") {
+		t.Fatal("evicted subscriber still registered")
+	}
+	Unsubscribe(sub)
+}
+
+func TestPublishKeyFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		key  string
+		want int
+	}{
+		{name: "no filter", keys: nil, key: "temp", want: 1},
+		{name: "matching key", keys: []string{"temp"}, key: "temp", want: 1},
+		{name: "other key", keys: []string{"hum"}, key: "temp", want: 0},
+		{name: "empty key ignored", keys: []string{""}, key: "temp", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := Subscribe([]string{"dev-filter"}, tt.keys, 4)
+			defer Unsubscribe(sub)
+			Publish([]Point{{DeviceId: "dev-filter", Key: tt.key}})
+			if got := len(sub.C); got != tt.want {
+				t.Fatalf("queued = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
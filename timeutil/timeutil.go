@@ -0,0 +1,115 @@
+package timeutil
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// 输出时间格式
+const (
+	FormatMs      = "ms"      // 毫秒时间戳
+	FormatUs      = "us"      // 微秒时间戳
+	FormatRFC3339 = "rfc3339" // RFC3339字符串，带时区偏移
+)
+
+// 数据库返回的时间字符串格式(time.Time.String())
+const layout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+var (
+	defaultLoc    = time.Local
+	defaultFormat = FormatMs
+)
+
+// 读取全局默认时区(time.timezone)和输出格式(time.format)，配置错误时使用默认值
+func TimeInit() {
+	if tz := viper.GetString("time.timezone"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			slog.Error("invalid time.timezone, using local timezone", "timezone", tz, "error", err)
+		} else {
+			defaultLoc = loc
+		}
+	}
+	if f := viper.GetString("time.format"); f != "" {
+		format, err := parseFormat(f)
+		if err != nil {
+			slog.Error("invalid time.format, using ms", "error", err)
+		} else {
+			defaultFormat = format
+		}
+	}
+}
+
+func parseFormat(f string) (string, error) {
+	switch f = strings.ToLower(f); f {
+	case FormatMs, FormatUs, FormatRFC3339:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid time format %q, expected ms, us or rfc3339", f)
+}
+
+// 单次请求的时区和输出格式
+type Formatter struct {
+	loc    *time.Location
+	format string
+}
+
+// timezone、format为空时使用全局默认值
+func New(timezone, format string) (*Formatter, error) {
+	f := &Formatter{loc: defaultLoc, format: defaultFormat}
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", timezone)
+		}
+		f.loc = loc
+	}
+	if format != "" {
+		var err error
+		if f.format, err = parseFormat(format); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// 默认时区和格式
+func Default() *Formatter {
+	return &Formatter{loc: defaultLoc, format: defaultFormat}
+}
+
+func (f *Formatter) Location() *time.Location {
+	return f.loc
+}
+
+// 按输出格式转换时间：ms/us为int64，rfc3339为字符串
+func (f *Formatter) Format(t time.Time) interface{} {
+	switch f.format {
+	case FormatUs:
+		return t.UnixMicro()
+	case FormatRFC3339:
+		return t.In(f.loc).Format(time.RFC3339Nano)
+	default:
+		return t.UnixMilli()
+	}
+}
+
+// 转换数据库返回的时间值
+func Parse(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return time.Parse(layout, t)
+	}
+	return time.Time{}, fmt.Errorf("unsupported time value %v", v)
+}
+
+// 请求参数中的毫秒时间戳
+func FromMillis(ms int64) time.Time {
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,111 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"thingspanel-TDengine/logger"
+
+	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "thingspanel-TDengine"
+
+// 开启追踪时的TracerProvider，退出时用于上报剩余span
+var provider *sdktrace.TracerProvider
+
+// 初始化链路追踪，未开启时使用otel默认的空实现
+func TracingInit() {
+	// 传播器总是注册，保证上游的trace上下文可以继续传递
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !viper.GetBool("tracing.enable") {
+		return
+	}
+
+	exporter, err := newExporter()
+	if err != nil {
+		logger.Tracing.Error("failed to create trace exporter", "error", err)
+		return
+	}
+
+	serviceName := viper.GetString("tracing.service_name")
+	if serviceName == "" {
+		serviceName = instrumentationName
+	}
+	ratio := 1.0
+	if viper.IsSet("tracing.sample_ratio") {
+		ratio = viper.GetFloat64("tracing.sample_ratio")
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(tp)
+	provider = tp
+	logger.Tracing.Info("tracing enabled", "exporter", viper.GetString("tracing.exporter"))
+}
+
+// 上报缓冲中的span并关闭exporter，未开启追踪时直接返回
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+// otlp: 通过gRPC上报到OTLP采集器；stdout/file: 本地调试输出
+func newExporter() (sdktrace.SpanExporter, error) {
+	switch exporter := viper.GetString("tracing.exporter"); exporter {
+	case "", "otlp":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(viper.GetString("tracing.endpoint"))}
+		if viper.GetBool("tracing.insecure") {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptracegrpc.New(context.Background(), opts...)
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	case "file":
+		f, err := os.OpenFile(viper.GetString("tracing.file"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return stdouttrace.New(stdouttrace.WithWriter(f))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", exporter)
+	}
+}
+
+// 开始一个span
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// 结束span，err不为空时记录错误
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// 数据库查询span属性，statement为不含参数值的SQL
+func DbAttributes(statement string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.DBSystemKey.String("tdengine"),
+		semconv.DBStatement(statement),
+	}
+}